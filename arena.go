@@ -1,6 +1,8 @@
 package armap
 
 import (
+	"math"
+	"reflect"
 	"unsafe"
 
 	"github.com/alecthomas/arena"
@@ -8,6 +10,7 @@ import (
 
 type Arena interface {
 	get() *arena.Arena
+	chunkSize() int
 
 	Reset()
 	Release()
@@ -22,18 +25,76 @@ func (w *wrapArena) get() *arena.Arena {
 	return w.ar
 }
 
+func (w *wrapArena) chunkSize() int {
+	return w.bufferSize
+}
+
 func (w *wrapArena) Reset() {
 	w.ar.Reset()
 }
 
 func (w *wrapArena) Release() {
 	w.ar = nil
-	w.ar = arena.Create(w.bufferSize)
+	w.ar = createArena(w.bufferSize)
 }
 
+// createArena creates an arena that adds a new chunk of bufferSize whenever the current one is exhausted.
+// without a limit the underlying arena wraps around and reuses the first chunk.
+func createArena(bufferSize int) *arena.Arena {
+	return arena.Create(bufferSize, arena.WithLimit(math.MaxInt))
+}
+
+// NewArena creates an Arena that grows in chunks of bufferSize bytes.
+// bufferSize is also the maximum size of a single allocation.
 func NewArena(bufferSize int) Arena {
-	ar := arena.Create(bufferSize)
-	return &wrapArena{ar, bufferSize}
+	return &wrapArena{createArena(bufferSize), bufferSize}
+}
+
+// cloneString copies the bytes of s into arena memory
+func cloneString(a Arena, s string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	// round capacity up to keep following arena allocations pointer aligned
+	align := int(unsafe.Alignof(uintptr(0)))
+	buf := arena.Make[byte](a.get(), len(s), (len(s)+align-1)&^(align-1))
+	copy(buf, s)
+	return unsafe.String(unsafe.SliceData(buf), len(s))
+}
+
+// makeFit makes slice in the arena, or on the heap when it is larger than a single arena buffer.
+// heap backed slices must only be referenced from GC scanned memory.
+func makeFit[T any](a Arena, size, capacity int) []T {
+	var t T
+	if a.chunkSize() < int(unsafe.Sizeof(t))*capacity {
+		return make([]T, size, capacity)
+	}
+	return arena.Make[T](a.get(), size, capacity)
+}
+
+// hasPointers reports whether values of T reference memory the GC has to track
+func hasPointers[T any]() bool {
+	return typeHasPointers(reflect.TypeFor[T]())
+}
+
+func typeHasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if typeHasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	case reflect.Array:
+		return 0 < t.Len() && typeHasPointers(t.Elem())
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	default:
+		return true
+	}
 }
 
 type TypeArena[T any] interface {
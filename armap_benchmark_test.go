@@ -36,6 +36,28 @@ func BenchmarkMap(b *testing.B) {
 	})
 }
 
+func BenchmarkMapBatch(b *testing.B) {
+	b.Run("armap", func(tb *testing.B) {
+		a := NewArena(1 * 1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a)
+		for i := 0; i < tb.N; i += 1 {
+			m.Set(i, i)
+		}
+	})
+	b.Run("armap/SetMany", func(tb *testing.B) {
+		a := NewArena(1 * 1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a)
+		keys := make([]int, tb.N)
+		for i := range keys {
+			keys[i] = i
+		}
+		tb.ResetTimer()
+		m.SetMany(keys, keys)
+	})
+}
+
 func BenchmarkSet(b *testing.B) {
 	b.Run("map", func(tb *testing.B) {
 		m := make(map[int]struct{}, 32)
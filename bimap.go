@@ -0,0 +1,94 @@
+package armap
+
+import (
+	"errors"
+)
+
+var (
+	ErrValueExists = errors.New("armap: value is already mapped to another key")
+)
+
+// BiMap is one-to-one mapping between keys and values with lookups in both directions.
+type BiMap[K comparable, V comparable] struct {
+	fwd *Map[K, V]
+	rev *Map[V, K]
+}
+
+func (bm *BiMap[K, V]) Len() int {
+	return bm.fwd.Len()
+}
+
+// Set maps key to value, replacing the previous value of key.
+// it returns ErrValueExists if value is already mapped to another key,
+// and ErrFull if key is new and the BiMap created by WithFixedCapacity is full.
+func (bm *BiMap[K, V]) Set(key K, value V) error {
+	if k, ok := bm.rev.Get(value); ok {
+		if k == key {
+			return nil
+		}
+		return ErrValueExists
+	}
+	if bm.fwd.full() && bm.fwd.find(bm.fwd.Hash(key), key) == nil {
+		return ErrFull
+	}
+	if old, ok := bm.fwd.Set(key, value); ok {
+		bm.rev.Delete(old)
+	}
+	bm.rev.Set(value, key)
+	return nil
+}
+
+// Replace maps key to value, removing any existing mapping of key or value.
+// a new pair is not inserted when the BiMap created by WithFixedCapacity is full.
+func (bm *BiMap[K, V]) Replace(key K, value V) {
+	if bm.fwd.full() && bm.fwd.find(bm.fwd.Hash(key), key) == nil && bm.rev.find(bm.rev.Hash(value), value) == nil {
+		return
+	}
+	if k, ok := bm.rev.Delete(value); ok {
+		bm.fwd.Delete(k)
+	}
+	if old, ok := bm.fwd.Set(key, value); ok {
+		bm.rev.Delete(old)
+	}
+	bm.rev.Set(value, key)
+}
+
+func (bm *BiMap[K, V]) GetByKey(key K) (V, bool) {
+	return bm.fwd.Get(key)
+}
+
+func (bm *BiMap[K, V]) GetByValue(value V) (K, bool) {
+	return bm.rev.Get(value)
+}
+
+func (bm *BiMap[K, V]) DeleteByKey(key K) (V, bool) {
+	value, ok := bm.fwd.Delete(key)
+	if ok {
+		bm.rev.Delete(value)
+	}
+	return value, ok
+}
+
+func (bm *BiMap[K, V]) DeleteByValue(value V) (K, bool) {
+	key, ok := bm.rev.Delete(value)
+	if ok {
+		bm.fwd.Delete(key)
+	}
+	return key, ok
+}
+
+func (bm *BiMap[K, V]) Scan(iter func(K, V) bool) {
+	bm.fwd.Scan(iter)
+}
+
+func (bm *BiMap[K, V]) Clear() {
+	bm.fwd.Clear()
+	bm.rev.Clear()
+}
+
+func NewBiMap[K comparable, V comparable](arena Arena, funcs ...OptionFunc) *BiMap[K, V] {
+	return &BiMap[K, V]{
+		fwd: NewMap[K, V](arena, funcs...),
+		rev: NewMap[V, K](arena, funcs...),
+	}
+}
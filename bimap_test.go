@@ -0,0 +1,110 @@
+package armap
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestBiMap(t *testing.T) {
+	t.Run("Set", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewBiMap[int, string](a)
+
+		if err := m.Set(1, "one"); err != nil {
+			tt.Errorf("unexpected error: %+v", err)
+		}
+		if err := m.Set(2, "two"); err != nil {
+			tt.Errorf("unexpected error: %+v", err)
+		}
+		if err := m.Set(3, "one"); errors.Is(err, ErrValueExists) != true {
+			tt.Errorf("expect ErrValueExists: %+v", err)
+		}
+		if err := m.Set(1, "one"); err != nil {
+			tt.Errorf("same mapping: %+v", err)
+		}
+
+		// update value of key 2
+		if err := m.Set(2, "deux"); err != nil {
+			tt.Errorf("unexpected error: %+v", err)
+		}
+		if _, ok := m.GetByValue("two"); ok {
+			tt.Errorf("two is replaced")
+		}
+		if k, ok := m.GetByValue("deux"); ok != true || k != 2 {
+			tt.Errorf("deux key = %d", k)
+		}
+		if v, ok := m.GetByKey(2); ok != true || v != "deux" {
+			tt.Errorf("2 value = %s", v)
+		}
+		if m.Len() != 2 {
+			tt.Errorf("len = %d, expect 2", m.Len())
+		}
+	})
+
+	t.Run("Replace/Delete", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewBiMap[int, string](a)
+
+		m.Set(1, "one")
+		m.Set(2, "two")
+		m.Replace(3, "one")
+
+		if _, ok := m.GetByKey(1); ok {
+			tt.Errorf("1 is replaced")
+		}
+		if k, ok := m.GetByValue("one"); ok != true || k != 3 {
+			tt.Errorf("one key = %d", k)
+		}
+
+		if v, ok := m.DeleteByKey(2); ok != true || v != "two" {
+			tt.Errorf("delete 2 = %s", v)
+		}
+		if _, ok := m.GetByValue("two"); ok {
+			tt.Errorf("two is deleted")
+		}
+		if k, ok := m.DeleteByValue("one"); ok != true || k != 3 {
+			tt.Errorf("delete one = %d", k)
+		}
+		if _, ok := m.GetByKey(3); ok {
+			tt.Errorf("3 is deleted")
+		}
+
+		count := 0
+		m.Scan(func(k int, v string) bool {
+			count += 1
+			return true
+		})
+		if count != 0 || m.Len() != 0 {
+			tt.Errorf("count = %d len = %d", count, m.Len())
+		}
+	})
+
+	t.Run("WithFixedCapacity", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		bm := NewBiMap[int, string](a, WithFixedCapacity(8))
+
+		i := 0
+		for ; ; i += 1 {
+			if err := bm.Set(i, strconv.Itoa(i)); err != nil {
+				if errors.Is(err, ErrFull) != true {
+					tt.Errorf("unexpected error: %+v", err)
+				}
+				break
+			}
+		}
+		bm.Replace(-1, "-1")
+		if _, ok := bm.GetByKey(-1); ok {
+			tt.Errorf("-1 must not be inserted")
+		}
+		if err := bm.Set(0, "zero"); err != nil {
+			tt.Errorf("update existing key: %+v", err)
+		}
+		if bm.Len() != i {
+			tt.Errorf("len = %d, expect %d", bm.Len(), i)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package armap
+
+import (
+	"encoding/binary"
+	"iter"
+	"math/bits"
+)
+
+// BitSet is dense set of uint32 whose words are allocated in the arena.
+// word arrays larger than the arena buffer are allocated on the heap.
+type BitSet struct {
+	arena Arena
+	words []uint64
+	count int
+}
+
+func (s *BitSet) grow(n int) {
+	if n <= len(s.words) {
+		return
+	}
+	size := max(len(s.words)*2, n, 8)
+	words := makeFit[uint64](s.arena, size, size)
+	copy(words, s.words)
+	s.words = words
+}
+
+func (s *BitSet) Len() int {
+	return s.count
+}
+
+// Add adds x and reports whether it was newly added
+func (s *BitSet) Add(x uint32) bool {
+	i := int(x >> 6)
+	s.grow(i + 1)
+	mask := uint64(1) << (x & 63)
+	if s.words[i]&mask != 0 {
+		return false
+	}
+	s.words[i] |= mask
+	s.count += 1
+	return true
+}
+
+func (s *BitSet) Contains(x uint32) bool {
+	i := int(x >> 6)
+	if len(s.words) <= i {
+		return false
+	}
+	return s.words[i]&(uint64(1)<<(x&63)) != 0
+}
+
+func (s *BitSet) Remove(x uint32) bool {
+	if s.Contains(x) != true {
+		return false
+	}
+	s.words[x>>6] &^= uint64(1) << (x & 63)
+	s.count -= 1
+	return true
+}
+
+// Rank returns the number of members less than or equal to x
+func (s *BitSet) Rank(x uint32) int {
+	i := int(x >> 6)
+	rank := 0
+	for _, w := range s.words[:min(i, len(s.words))] {
+		rank += bits.OnesCount64(w)
+	}
+	if i < len(s.words) {
+		rank += bits.OnesCount64(s.words[i] & (^uint64(0) >> (63 - (x & 63))))
+	}
+	return rank
+}
+
+// Select returns the i-th smallest member (0-based)
+func (s *BitSet) Select(i int) (uint32, bool) {
+	if i < 0 || s.count <= i {
+		return 0, false
+	}
+	for wi, w := range s.words {
+		n := bits.OnesCount64(w)
+		if i < n {
+			return uint32(wi<<6) + selectInWord(w, i), true
+		}
+		i -= n
+	}
+	return 0, false
+}
+
+func (s *BitSet) Union(other *BitSet) {
+	s.grow(len(other.words))
+	for i, w := range other.words {
+		s.words[i] |= w
+	}
+	s.recount()
+}
+
+func (s *BitSet) Intersect(other *BitSet) {
+	for i := range s.words {
+		if i < len(other.words) {
+			s.words[i] &= other.words[i]
+		} else {
+			s.words[i] = 0
+		}
+	}
+	s.recount()
+}
+
+func (s *BitSet) Difference(other *BitSet) {
+	for i := range min(len(s.words), len(other.words)) {
+		s.words[i] &^= other.words[i]
+	}
+	s.recount()
+}
+
+func (s *BitSet) recount() {
+	s.count = 0
+	for _, w := range s.words {
+		s.count += bits.OnesCount64(w)
+	}
+}
+
+// All iterates members in ascending order
+func (s *BitSet) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for wi, w := range s.words {
+			for w != 0 {
+				tz := bits.TrailingZeros64(w)
+				if yield(uint32(wi<<6+tz)) != true {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+func (s *BitSet) Clear() {
+	clear(s.words)
+	s.count = 0
+}
+
+func (s *BitSet) MarshalBinary() ([]byte, error) {
+	n := len(s.words)
+	for 0 < n && s.words[n-1] == 0 {
+		n -= 1
+	}
+	data := make([]byte, 4+n*8)
+	binary.LittleEndian.PutUint32(data, uint32(n))
+	for i, w := range s.words[:n] {
+		binary.LittleEndian.PutUint64(data[4+i*8:], w)
+	}
+	return data, nil
+}
+
+func (s *BitSet) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return ErrInvalidData
+	}
+	n := int(binary.LittleEndian.Uint32(data))
+	if len(data) != 4+n*8 {
+		return ErrInvalidData
+	}
+	s.Clear()
+	s.grow(n)
+	for i := 0; i < n; i += 1 {
+		s.words[i] = binary.LittleEndian.Uint64(data[4+i*8:])
+	}
+	s.recount()
+	return nil
+}
+
+func NewBitSet(arena Arena) *BitSet {
+	return &BitSet{
+		arena: arena,
+		words: nil,
+		count: 0,
+	}
+}
+
+// selectInWord returns the position of the i-th set bit of w
+func selectInWord(w uint64, i int) uint32 {
+	for ; 0 < i; i -= 1 {
+		w &= w - 1
+	}
+	return uint32(bits.TrailingZeros64(w))
+}
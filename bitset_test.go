@@ -0,0 +1,117 @@
+package armap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBitSet(t *testing.T) {
+	t.Run("Add/Remove", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s := NewBitSet(a)
+
+		for _, x := range []uint32{1, 5, 64, 100, 1000} {
+			if s.Add(x) != true {
+				tt.Errorf("%d is new", x)
+			}
+		}
+		if s.Add(5) {
+			tt.Errorf("5 exists")
+		}
+		if s.Len() != 5 {
+			tt.Errorf("len = %d, expect 5", s.Len())
+		}
+		if s.Contains(64) != true || s.Contains(63) || s.Contains(100_000) {
+			tt.Errorf("contains mismatch")
+		}
+		if r := s.Rank(64); r != 3 {
+			tt.Errorf("rank(64) = %d, expect 3", r)
+		}
+		if r := s.Rank(63); r != 2 {
+			tt.Errorf("rank(63) = %d, expect 2", r)
+		}
+		if x, ok := s.Select(3); ok != true || x != 100 {
+			tt.Errorf("select(3) = %d", x)
+		}
+		if _, ok := s.Select(5); ok {
+			tt.Errorf("select(5) out of range")
+		}
+		if s.Remove(64) != true || s.Remove(64) {
+			tt.Errorf("remove 64")
+		}
+		if all := slices.Collect(s.All()); slices.Equal(all, []uint32{1, 5, 100, 1000}) != true {
+			tt.Errorf("all = %v", all)
+		}
+	})
+
+	t.Run("exceed_buffer", func(tt *testing.T) {
+		// word array is larger than the arena buffer
+		a := NewArena(64 * 1024)
+		defer a.Release()
+		s := NewBitSet(a)
+
+		expect := []uint32{1, 70_000, 1_000_000, 10_000_000}
+		for _, x := range expect {
+			if s.Add(x) != true {
+				tt.Errorf("%d is new", x)
+			}
+		}
+		if all := slices.Collect(s.All()); slices.Equal(all, expect) != true {
+			tt.Errorf("all = %v", all)
+		}
+		if r := s.Rank(10_000_000); r != 4 {
+			tt.Errorf("rank = %d, expect 4", r)
+		}
+	})
+
+	t.Run("algebra", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s1 := NewBitSet(a)
+		s2 := NewBitSet(a)
+		for i := uint32(0); i < 200; i += 2 {
+			s1.Add(i)
+		}
+		for i := uint32(0); i < 300; i += 3 {
+			s2.Add(i)
+		}
+
+		s1.Intersect(s2)
+		if s1.Len() != 34 {
+			tt.Errorf("intersect len = %d, expect 34", s1.Len())
+		}
+		s1.Union(s2)
+		if s1.Len() != s2.Len() {
+			tt.Errorf("union len = %d, expect %d", s1.Len(), s2.Len())
+		}
+		s1.Difference(s2)
+		if s1.Len() != 0 {
+			tt.Errorf("difference len = %d, expect 0", s1.Len())
+		}
+	})
+
+	t.Run("MarshalBinary", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s1 := NewBitSet(a)
+		for i := uint32(0); i < 1000; i += 7 {
+			s1.Add(i)
+		}
+		data, err := s1.MarshalBinary()
+		if err != nil {
+			tt.Fatalf("unexpected error: %+v", err)
+		}
+		s2 := NewBitSet(a)
+		if err := s2.UnmarshalBinary(data); err != nil {
+			tt.Fatalf("unexpected error: %+v", err)
+		}
+		if slices.Equal(slices.Collect(s1.All()), slices.Collect(s2.All())) != true {
+			tt.Errorf("mismatch after unmarshal")
+		}
+		if err := s2.UnmarshalBinary(data[:5]); errors.Is(err, ErrInvalidData) != true {
+			tt.Errorf("expect ErrInvalidData: %+v", err)
+		}
+	})
+}
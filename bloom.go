@@ -0,0 +1,154 @@
+package armap
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+
+	"github.com/dolthub/maphash"
+)
+
+var (
+	ErrIncompatible = errors.New("armap: incompatible size or parameters")
+	ErrInvalidData  = errors.New("armap: invalid binary data")
+)
+
+// BloomFilter is approximate membership set without false negatives.
+// bit positions depend on the hasher seed, so Union and UnmarshalBinary
+// require filters sharing the same hasher (see WithHasher).
+type BloomFilter[K comparable] struct {
+	hasher maphash.Hasher[K]
+	bits   []uint64
+	m      uint64 // number of bits
+	k      uint32 // number of hash functions
+}
+
+// locations derives k bit positions from one 64bit hash (Kirsch-Mitzenmacher double hashing)
+func (f *BloomFilter[K]) locations(key K, fn func(uint64) bool) {
+	h := f.hasher.Hash(key)
+	h1 := h & 0xffffffff
+	h2 := (h >> 32) | 1
+	for i := uint64(0); i < uint64(f.k); i += 1 {
+		if fn((h1+i*h2)%f.m) != true {
+			return
+		}
+	}
+}
+
+func (f *BloomFilter[K]) Add(key K) {
+	f.locations(key, func(pos uint64) bool {
+		f.bits[pos>>6] |= 1 << (pos & 63)
+		return true
+	})
+}
+
+// MayContain reports false if key was definitely not added
+func (f *BloomFilter[K]) MayContain(key K) bool {
+	found := true
+	f.locations(key, func(pos uint64) bool {
+		if f.bits[pos>>6]&(1<<(pos&63)) == 0 {
+			found = false
+		}
+		return found
+	})
+	return found
+}
+
+// Union merges other into f
+func (f *BloomFilter[K]) Union(other *BloomFilter[K]) error {
+	if f.m != other.m || f.k != other.k || sameHasher(f.hasher, other.hasher) != true {
+		return ErrIncompatible
+	}
+	for i, b := range other.bits {
+		f.bits[i] |= b
+	}
+	return nil
+}
+
+// EstimatedCount estimates the number of distinct keys added
+func (f *BloomFilter[K]) EstimatedCount() float64 {
+	x := 0
+	for _, b := range f.bits {
+		x += bits.OnesCount64(b)
+	}
+	m := float64(f.m)
+	if x == int(f.m) {
+		return math.Inf(1)
+	}
+	return -(m / float64(f.k)) * math.Log(1-float64(x)/m)
+}
+
+func (f *BloomFilter[K]) Hasher() maphash.Hasher[K] {
+	return f.hasher
+}
+
+func (f *BloomFilter[K]) Clear() {
+	clear(f.bits)
+}
+
+// bloomHeaderSize is m (8 bytes), k (4 bytes) and the hasher fingerprint (8 bytes)
+const bloomHeaderSize = 20
+
+// hasherFingerprint identifies the hasher seed, like sameHasher
+func (f *BloomFilter[K]) hasherFingerprint() uint64 {
+	var zero K
+	return f.hasher.Hash(zero)
+}
+
+// MarshalBinary encodes the parameters, the hasher fingerprint and the bit array.
+// maphash seeds are random per process and cannot be set, so the data can only be restored
+// by a filter sharing the hasher in the same process; it cannot be read back after a restart.
+func (f *BloomFilter[K]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, bloomHeaderSize+len(f.bits)*8)
+	binary.LittleEndian.PutUint64(data[0:], f.m)
+	binary.LittleEndian.PutUint32(data[8:], f.k)
+	binary.LittleEndian.PutUint64(data[12:], f.hasherFingerprint())
+	for i, b := range f.bits {
+		binary.LittleEndian.PutUint64(data[bloomHeaderSize+i*8:], b)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary restores data encoded by MarshalBinary, keeping the hasher of f.
+// it returns ErrIncompatible when the data was encoded by a filter with a different hasher.
+func (f *BloomFilter[K]) UnmarshalBinary(data []byte) error {
+	if len(data) < bloomHeaderSize {
+		return ErrInvalidData
+	}
+	m := binary.LittleEndian.Uint64(data[0:])
+	k := binary.LittleEndian.Uint32(data[8:])
+	if m == 0 || m%64 != 0 || k == 0 || uint64(len(data)-bloomHeaderSize) != m/8 {
+		return ErrInvalidData
+	}
+	if binary.LittleEndian.Uint64(data[12:]) != f.hasherFingerprint() {
+		return ErrIncompatible
+	}
+	f.m = m
+	f.k = k
+	f.bits = make([]uint64, m/64)
+	for i := range f.bits {
+		f.bits[i] = binary.LittleEndian.Uint64(data[bloomHeaderSize+i*8:])
+	}
+	return nil
+}
+
+// NewBloomFilter creates BloomFilter sized for expectedItems keys at false positive rate fpRate.
+func NewBloomFilter[K comparable](expectedItems int, fpRate float64, funcs ...OptionFunc) *BloomFilter[K] {
+	opt := newOption()
+	for _, fn := range funcs {
+		fn(opt)
+	}
+
+	n := float64(max(expectedItems, 1))
+	m := uint64(math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	m = max((m+63)&^63, 64)
+	k := uint32(max(math.Round(float64(m)/n*math.Ln2), 1))
+
+	return &BloomFilter[K]{
+		hasher: newHasher[K](opt),
+		bits:   make([]uint64, m/64),
+		m:      m,
+		k:      k,
+	}
+}
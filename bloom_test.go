@@ -0,0 +1,85 @@
+package armap
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	t.Run("MayContain", func(tt *testing.T) {
+		N := 10_000
+		f := NewBloomFilter[int](N, 0.01)
+		for i := 0; i < N; i += 1 {
+			f.Add(i)
+		}
+		for i := 0; i < N; i += 1 {
+			if f.MayContain(i) != true {
+				tt.Errorf("key %d is added", i)
+			}
+		}
+
+		fp := 0
+		for i := N; i < N*2; i += 1 {
+			if f.MayContain(i) {
+				fp += 1
+			}
+		}
+		if rate := float64(fp) / float64(N); 0.02 < rate {
+			tt.Errorf("false positive rate = %f", rate)
+		}
+
+		if est := f.EstimatedCount(); math.Abs(est-float64(N)) > float64(N)*0.05 {
+			tt.Errorf("estimated = %f, expect ~%d", est, N)
+		}
+	})
+
+	t.Run("Union", func(tt *testing.T) {
+		f1 := NewBloomFilter[string](100, 0.01)
+		f2 := NewBloomFilter[string](100, 0.01, WithHasher(f1.Hasher()))
+		f1.Add("foo")
+		f2.Add("bar")
+
+		if err := f1.Union(f2); err != nil {
+			tt.Fatalf("unexpected error: %+v", err)
+		}
+		if f1.MayContain("foo") != true || f1.MayContain("bar") != true {
+			tt.Errorf("union contains foo and bar")
+		}
+
+		f3 := NewBloomFilter[string](100, 0.01)
+		if err := f1.Union(f3); errors.Is(err, ErrIncompatible) != true {
+			tt.Errorf("expect ErrIncompatible: %+v", err)
+		}
+	})
+
+	t.Run("MarshalBinary", func(tt *testing.T) {
+		f1 := NewBloomFilter[int](1000, 0.001)
+		for i := 0; i < 1000; i += 1 {
+			f1.Add(i)
+		}
+		data, err := f1.MarshalBinary()
+		if err != nil {
+			tt.Fatalf("unexpected error: %+v", err)
+		}
+
+		f2 := NewBloomFilter[int](1, 0.5, WithHasher(f1.Hasher()))
+		if err := f2.UnmarshalBinary(data); err != nil {
+			tt.Fatalf("unexpected error: %+v", err)
+		}
+		for i := 0; i < 1000; i += 1 {
+			if f2.MayContain(i) != true {
+				tt.Errorf("key %d is added", i)
+			}
+		}
+		if err := f2.UnmarshalBinary(data[:20]); errors.Is(err, ErrInvalidData) != true {
+			tt.Errorf("expect ErrInvalidData: %+v", err)
+		}
+
+		// filter with its own random hasher must reject the data
+		f3 := NewBloomFilter[int](1000, 0.001)
+		if err := f3.UnmarshalBinary(data); errors.Is(err, ErrIncompatible) != true {
+			tt.Errorf("expect ErrIncompatible: %+v", err)
+		}
+	})
+}
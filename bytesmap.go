@@ -0,0 +1,121 @@
+package armap
+
+import (
+	"unsafe"
+)
+
+// bytesView returns string that shares memory with b, b must not be modified while in use
+func bytesView(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
+
+func stringView(s string) []byte {
+	return unsafe.Slice(unsafe.StringData(s), len(s))
+}
+
+// BytesMap is Map keyed by []byte.
+// keys are copied into the arena once on insert and looked up without allocation.
+type BytesMap[V any] struct {
+	m *Map[string, V]
+}
+
+func (b *BytesMap[V]) Len() int {
+	return b.m.Len()
+}
+
+// Set inserts or updates key.
+// a new key is not inserted when the BytesMap created by WithFixedCapacity is full; use TrySet to detect it.
+func (b *BytesMap[V]) Set(key []byte, value V) (old V, found bool) {
+	old, found, _ = b.TrySet(key, value)
+	return old, found
+}
+
+// TrySet is Set that returns ErrFull when key is new and the BytesMap created by WithFixedCapacity is full
+func (b *BytesMap[V]) TrySet(key []byte, value V) (old V, found bool, err error) {
+	k := bytesView(key)
+	h := b.m.Hash(k)
+	if bk := b.m.find(h, k); bk != nil {
+		old = bk.value
+		bk.value = NewTypeArena[V](b.m.arena).Clone(value)
+		return old, true, nil
+	}
+	// check before copying the key, so that a rejected key does not use arena memory
+	if b.m.full() {
+		return old, false, ErrFull
+	}
+	old, found = b.m.SetHashed(h, cloneString(b.m.arena, k), value)
+	return old, found, nil
+}
+
+func (b *BytesMap[V]) Get(key []byte) (V, bool) {
+	return b.m.Get(bytesView(key))
+}
+
+func (b *BytesMap[V]) Delete(key []byte) (V, bool) {
+	return b.m.Delete(bytesView(key))
+}
+
+// Scan iterates all entries, key refers to arena memory and must not be modified.
+func (b *BytesMap[V]) Scan(iter func([]byte, V) bool) {
+	b.m.Scan(func(key string, value V) bool {
+		return iter(stringView(key), value)
+	})
+}
+
+func (b *BytesMap[V]) Clear() {
+	b.m.Clear()
+}
+
+func NewBytesMap[V any](arena Arena, funcs ...OptionFunc) *BytesMap[V] {
+	return &BytesMap[V]{
+		m: NewMap[string, V](arena, funcs...),
+	}
+}
+
+// BytesSet is Set keyed by []byte.
+type BytesSet struct {
+	m *BytesMap[setValue]
+}
+
+func (s *BytesSet) Len() int {
+	return s.m.Len()
+}
+
+// Add adds key, a new key is not added when the BytesSet created by WithFixedCapacity is full; use TryAdd to detect it
+func (s *BytesSet) Add(key []byte) bool {
+	_, ok := s.m.Set(key, setValue{})
+	return ok
+}
+
+// TryAdd is Add that returns ErrFull when key is new and the BytesSet created by WithFixedCapacity is full
+func (s *BytesSet) TryAdd(key []byte) (bool, error) {
+	_, ok, err := s.m.TrySet(key, setValue{})
+	return ok, err
+}
+
+func (s *BytesSet) Contains(key []byte) bool {
+	_, ok := s.m.Get(key)
+	return ok
+}
+
+func (s *BytesSet) Delete(key []byte) bool {
+	_, ok := s.m.Delete(key)
+	return ok
+}
+
+// Scan iterates all keys, key refers to arena memory and must not be modified.
+func (s *BytesSet) Scan(iter func([]byte) bool) {
+	s.m.Scan(func(key []byte, value setValue) bool {
+		return iter(key)
+	})
+}
+
+func (s *BytesSet) Clear() {
+	s.m.Clear()
+}
+
+func NewBytesSet(arena Arena, funcs ...OptionFunc) *BytesSet {
+	return &BytesSet{
+		m: NewBytesMap[setValue](arena, funcs...),
+	}
+}
@@ -0,0 +1,158 @@
+package armap
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestBytesMap(t *testing.T) {
+	t.Run("reuse_buffer", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewBytesMap[int](a)
+
+		buf := make([]byte, 0, 32)
+		for i := 0; i < 1000; i += 1 {
+			buf = strconv.AppendInt(buf[:0], int64(i), 10)
+			if _, ok := m.Set(buf, i); ok {
+				tt.Errorf("key %s is new key", buf)
+			}
+		}
+		// overwrite buffer, stored keys must not change
+		for i := range buf {
+			buf[i] = 'x'
+		}
+
+		if m.Len() != 1000 {
+			tt.Errorf("len = %d, expect 1000", m.Len())
+		}
+		for i := 0; i < 1000; i += 1 {
+			buf = strconv.AppendInt(buf[:0], int64(i), 10)
+			if v, ok := m.Get(buf); ok != true || v != i {
+				tt.Errorf("key %s value = %d", buf, v)
+			}
+		}
+
+		buf = strconv.AppendInt(buf[:0], 10, 10)
+		if old, ok := m.Set(buf, -10); ok != true || old != 10 {
+			tt.Errorf("update old = %d", old)
+		}
+		if old, ok := m.Delete(buf); ok != true || old != -10 {
+			tt.Errorf("delete old = %d", old)
+		}
+		if _, ok := m.Get(buf); ok {
+			tt.Errorf("key %s deleted", buf)
+		}
+
+		count := 0
+		m.Scan(func(key []byte, value int) bool {
+			if string(key) != strconv.Itoa(value) {
+				tt.Errorf("key %s value %d", key, value)
+			}
+			count += 1
+			return true
+		})
+		if count != 999 {
+			tt.Errorf("scan count = %d, expect 999", count)
+		}
+	})
+
+	t.Run("exceed_buffer", func(tt *testing.T) {
+		// keys take much more than one arena buffer
+		a := NewArena(4 * 1024)
+		defer a.Release()
+		m := NewBytesMap[int](a)
+
+		buf := make([]byte, 0, 32)
+		for i := 0; i < 2000; i += 1 {
+			buf = append(strconv.AppendInt(buf[:0], int64(i), 10), "-key-padding"...)
+			m.Set(buf, i)
+		}
+		for i := 0; i < 2000; i += 1 {
+			buf = append(strconv.AppendInt(buf[:0], int64(i), 10), "-key-padding"...)
+			if v, ok := m.Get(buf); ok != true || v != i {
+				tt.Errorf("key %s value = %d, expect %d", buf, v, i)
+			}
+		}
+		m.Scan(func(key []byte, value int) bool {
+			if string(key) != strconv.Itoa(value)+"-key-padding" {
+				tt.Errorf("key %s value %d", key, value)
+			}
+			return true
+		})
+	})
+
+	t.Run("Get/noalloc", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewBytesMap[int](a)
+		m.Set([]byte("hello"), 1)
+
+		key := []byte("hello")
+		allocs := testing.AllocsPerRun(100, func() {
+			m.Get(key)
+		})
+		if allocs != 0 {
+			tt.Errorf("allocs = %f, expect 0", allocs)
+		}
+	})
+}
+
+func TestBytesMapFixedCapacity(t *testing.T) {
+	a := NewArena(1024 * 1024)
+	defer a.Release()
+	m := NewBytesMap[int](a, WithFixedCapacity(4))
+	s := NewBytesSet(a, WithFixedCapacity(4))
+
+	n := 0
+	for i := 0; i < 10; i += 1 {
+		key := []byte(strconv.Itoa(i))
+		_, _, err := m.TrySet(key, i)
+		_, serr := s.TryAdd(key)
+		if err != nil {
+			if errors.Is(err, ErrFull) != true || errors.Is(serr, ErrFull) != true {
+				t.Errorf("unexpected error: %+v %+v", err, serr)
+			}
+			continue
+		}
+		n += 1
+	}
+	if n == 0 || n == 10 {
+		t.Errorf("inserted = %d, expect rejected keys", n)
+	}
+	if m.Len() != n || s.Len() != n {
+		t.Errorf("len = %d %d, expect %d", m.Len(), s.Len(), n)
+	}
+	// existing keys can be updated while full
+	if old, ok, err := m.TrySet([]byte("0"), -1); err != nil || ok != true || old != 0 {
+		t.Errorf("update old = %d %v %+v", old, ok, err)
+	}
+	if _, ok := m.Get([]byte("9")); ok {
+		t.Errorf("9 is rejected")
+	}
+}
+
+func TestBytesSet(t *testing.T) {
+	a := NewArena(1024 * 1024)
+	defer a.Release()
+	s := NewBytesSet(a)
+
+	key := []byte("foo")
+	if ok := s.Add(key); ok {
+		t.Errorf("foo is new key")
+	}
+	key[0] = 'b'
+	if ok := s.Contains([]byte("foo")); ok != true {
+		t.Errorf("foo exists")
+	}
+	if ok := s.Contains(key); ok {
+		t.Errorf("boo not exists")
+	}
+	if ok := s.Delete([]byte("foo")); ok != true {
+		t.Errorf("foo exists")
+	}
+	if s.Len() != 0 {
+		t.Errorf("len = %d, expect 0", s.Len())
+	}
+}
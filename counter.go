@@ -0,0 +1,115 @@
+package armap
+
+import (
+	"container/heap"
+	"sort"
+)
+
+type CounterEntry[K comparable] struct {
+	Key   K
+	Count int64
+}
+
+// Counter counts occurrences per key, updating the bucket value in place with a single probe.
+type Counter[K comparable] struct {
+	m     *Map[K, int64]
+	total int64
+}
+
+func (c *Counter[K]) Len() int {
+	return c.m.Len()
+}
+
+func (c *Counter[K]) Inc(key K) int64 {
+	return c.Add(key, 1)
+}
+
+// Add adds delta to the count of key and returns the new count.
+// a new key is not counted and 0 is returned when the Counter created by WithFixedCapacity is full.
+func (c *Counter[K]) Add(key K, delta int64) int64 {
+	n, _ := c.TryAdd(key, delta)
+	return n
+}
+
+// TryAdd is Add that returns ErrFull when key is new and the Counter created by WithFixedCapacity is full
+func (c *Counter[K]) TryAdd(key K, delta int64) (int64, error) {
+	b, _, err := c.m.entry(c.m.Hash(key), key)
+	if err != nil {
+		return 0, err
+	}
+	b.value += delta
+	c.total += delta
+	return b.value, nil
+}
+
+func (c *Counter[K]) Get(key K) int64 {
+	v, _ := c.m.Get(key)
+	return v
+}
+
+// Total returns the sum of all counts
+func (c *Counter[K]) Total() int64 {
+	return c.total
+}
+
+// TopK returns up to n entries with the highest counts in descending order
+func (c *Counter[K]) TopK(n int) []CounterEntry[K] {
+	if n <= 0 {
+		return nil
+	}
+	h := make(counterHeap[K], 0, n)
+	c.m.Scan(func(key K, count int64) bool {
+		if len(h) < n {
+			heap.Push(&h, CounterEntry[K]{key, count})
+			return true
+		}
+		if h[0].Count < count {
+			h[0] = CounterEntry[K]{key, count}
+			heap.Fix(&h, 0)
+		}
+		return true
+	})
+	sort.Slice(h, func(i, j int) bool {
+		return h[i].Count > h[j].Count
+	})
+	return h
+}
+
+// Merge adds all counts of other into c
+func (c *Counter[K]) Merge(other *Counter[K]) {
+	other.m.Scan(func(key K, count int64) bool {
+		c.Add(key, count)
+		return true
+	})
+}
+
+func (c *Counter[K]) Clear() {
+	c.m.Clear()
+	c.total = 0
+}
+
+func NewCounter[K comparable](arena Arena, funcs ...OptionFunc) *Counter[K] {
+	return &Counter[K]{
+		m:     NewMap[K, int64](arena, funcs...),
+		total: 0,
+	}
+}
+
+// counterHeap is min-heap by Count
+type counterHeap[K comparable] []CounterEntry[K]
+
+func (h counterHeap[K]) Len() int           { return len(h) }
+func (h counterHeap[K]) Less(i, j int) bool { return h[i].Count < h[j].Count }
+func (h counterHeap[K]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *counterHeap[K]) Push(x any) {
+	*h = append(*h, x.(CounterEntry[K]))
+}
+
+func (h *counterHeap[K]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
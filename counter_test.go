@@ -0,0 +1,96 @@
+package armap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	t.Run("Add/TopK", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		c := NewCounter[string](a)
+
+		for i := 0; i < 5; i += 1 {
+			c.Inc("a")
+		}
+		c.Add("b", 10)
+		c.Add("c", 3)
+		c.Inc("d")
+
+		if v := c.Get("a"); v != 5 {
+			tt.Errorf("a = %d, expect 5", v)
+		}
+		if v := c.Get("none"); v != 0 {
+			tt.Errorf("none = %d, expect 0", v)
+		}
+		if c.Total() != 19 {
+			tt.Errorf("total = %d, expect 19", c.Total())
+		}
+		if c.Len() != 4 {
+			tt.Errorf("len = %d, expect 4", c.Len())
+		}
+
+		top := c.TopK(3)
+		expect := []CounterEntry[string]{{"b", 10}, {"a", 5}, {"c", 3}}
+		if len(top) != len(expect) {
+			tt.Fatalf("topk = %v", top)
+		}
+		for i := range expect {
+			if top[i] != expect[i] {
+				tt.Errorf("top[%d] = %v, expect %v", i, top[i], expect[i])
+			}
+		}
+		if top := c.TopK(10); len(top) != 4 {
+			tt.Errorf("topk = %v", top)
+		}
+	})
+
+	t.Run("Merge", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		c1 := NewCounter[int](a)
+		c2 := NewCounter[int](a)
+
+		for i := 0; i < 100; i += 1 {
+			c1.Add(i%10, 1)
+			c2.Add(i%20, 2)
+		}
+		c1.Merge(c2)
+
+		if c1.Total() != 300 {
+			tt.Errorf("total = %d, expect 300", c1.Total())
+		}
+		if v := c1.Get(0); v != 20 {
+			tt.Errorf("0 = %d, expect 20", v)
+		}
+		if v := c1.Get(15); v != 10 {
+			tt.Errorf("15 = %d, expect 10", v)
+		}
+	})
+
+	t.Run("WithFixedCapacity", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		c := NewCounter[int](a, WithFixedCapacity(8))
+
+		i := 0
+		for ; ; i += 1 {
+			if _, err := c.TryAdd(i, 1); err != nil {
+				if errors.Is(err, ErrFull) != true {
+					tt.Errorf("unexpected error: %+v", err)
+				}
+				break
+			}
+		}
+		if n := c.Add(-1, 1); n != 0 {
+			tt.Errorf("add new key to full counter = %d, expect 0", n)
+		}
+		if n := c.Add(0, 1); n != 2 {
+			tt.Errorf("add existing key = %d, expect 2", n)
+		}
+		if c.Len() != i {
+			tt.Errorf("len = %d, expect %d", c.Len(), i)
+		}
+	})
+}
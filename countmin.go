@@ -0,0 +1,132 @@
+package armap
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/dolthub/maphash"
+)
+
+// CountMinSketch estimates per-key counts in fixed memory, estimates never undercount.
+type CountMinSketch[K comparable] struct {
+	hasher   maphash.Hasher[K]
+	counters []uint64
+	width    uint64
+	depth    uint64
+}
+
+func (s *CountMinSketch[K]) positions(key K, fn func(uint64)) {
+	h := s.hasher.Hash(key)
+	h1 := h & 0xffffffff
+	h2 := (h >> 32) | 1
+	for i := uint64(0); i < s.depth; i += 1 {
+		fn(i*s.width + (h1+i*h2)%s.width)
+	}
+}
+
+func (s *CountMinSketch[K]) Add(key K, n uint64) {
+	s.positions(key, func(pos uint64) {
+		s.counters[pos] += n
+	})
+}
+
+func (s *CountMinSketch[K]) Estimate(key K) uint64 {
+	estimate := uint64(math.MaxUint64)
+	s.positions(key, func(pos uint64) {
+		estimate = min(estimate, s.counters[pos])
+	})
+	return estimate
+}
+
+// Merge adds counts of other into s, both must have the same dimensions and hasher
+func (s *CountMinSketch[K]) Merge(other *CountMinSketch[K]) error {
+	if s.width != other.width || s.depth != other.depth || sameHasher(s.hasher, other.hasher) != true {
+		return ErrIncompatible
+	}
+	for i, c := range other.counters {
+		s.counters[i] += c
+	}
+	return nil
+}
+
+func (s *CountMinSketch[K]) Hasher() maphash.Hasher[K] {
+	return s.hasher
+}
+
+func (s *CountMinSketch[K]) Clear() {
+	clear(s.counters)
+}
+
+// NewCountMinSketch creates CountMinSketch whose estimate exceeds the true count
+// by at most epsilon * total with probability 1 - delta.
+func NewCountMinSketch[K comparable](epsilon, delta float64, funcs ...OptionFunc) *CountMinSketch[K] {
+	opt := newOption()
+	for _, fn := range funcs {
+		fn(opt)
+	}
+
+	width := uint64(max(math.Ceil(math.E/epsilon), 1))
+	depth := uint64(max(math.Ceil(math.Log(1/delta)), 1))
+	return &CountMinSketch[K]{
+		hasher:   newHasher[K](opt),
+		counters: make([]uint64, width*depth),
+		width:    width,
+		depth:    depth,
+	}
+}
+
+// HeavyHitters tracks the approximate top-k keys using CountMinSketch and a small min-heap,
+// so that the smallest tracked key is found without scanning.
+type HeavyHitters[K comparable] struct {
+	sketch *CountMinSketch[K]
+	top    *IndexedHeap[K, int64]
+	k      int
+}
+
+func (h *HeavyHitters[K]) Add(key K, n uint64) {
+	h.sketch.Add(key, n)
+	estimate := int64(h.sketch.Estimate(key))
+
+	if _, ok := h.top.Get(key); ok || h.top.Len() < h.k {
+		h.top.Push(key, estimate)
+		return
+	}
+	if _, minCount, _ := h.top.Peek(); minCount < estimate {
+		h.top.Pop()
+		h.top.Push(key, estimate)
+	}
+}
+
+// Estimate returns the estimated count of key
+func (h *HeavyHitters[K]) Estimate(key K) uint64 {
+	return h.sketch.Estimate(key)
+}
+
+// Top returns the tracked keys in descending order of estimated count
+func (h *HeavyHitters[K]) Top() []CounterEntry[K] {
+	entries := make([]CounterEntry[K], 0, h.top.Len())
+	h.top.scan(func(key K, count int64) bool {
+		entries = append(entries, CounterEntry[K]{key, count})
+		return true
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Count > entries[j].Count
+	})
+	return entries
+}
+
+func (h *HeavyHitters[K]) Clear() {
+	h.sketch.Clear()
+	h.top.Clear()
+}
+
+func NewHeavyHitters[K comparable](arena Arena, k int, epsilon, delta float64, funcs ...OptionFunc) *HeavyHitters[K] {
+	return &HeavyHitters[K]{
+		sketch: NewCountMinSketch[K](epsilon, delta, funcs...),
+		top: NewIndexedHeap[K, int64](arena, func(a, b int64) bool {
+			return a < b
+		}, append(slices.Clone(funcs), WithCapacity(k))...),
+		k: k,
+	}
+}
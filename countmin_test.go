@@ -0,0 +1,96 @@
+package armap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	t.Run("Estimate", func(tt *testing.T) {
+		s := NewCountMinSketch[int](0.001, 0.01)
+		total := uint64(0)
+		for i := 0; i < 1000; i += 1 {
+			s.Add(i, uint64(i))
+			total += uint64(i)
+		}
+		overs := 0
+		for i := 0; i < 1000; i += 1 {
+			e := s.Estimate(i)
+			if e < uint64(i) {
+				tt.Errorf("key %d estimate = %d undercount", i, e)
+			}
+			if uint64(i)+uint64(float64(total)*0.001) < e {
+				overs += 1
+			}
+		}
+		// error bound holds with probability 1 - delta per key
+		if 20 < overs {
+			tt.Errorf("%d keys exceed error bound", overs)
+		}
+	})
+
+	t.Run("Merge", func(tt *testing.T) {
+		s1 := NewCountMinSketch[string](0.01, 0.01)
+		s2 := NewCountMinSketch[string](0.01, 0.01, WithHasher(s1.Hasher()))
+		s1.Add("a", 10)
+		s2.Add("a", 5)
+		if err := s1.Merge(s2); err != nil {
+			tt.Fatalf("unexpected error: %+v", err)
+		}
+		if e := s1.Estimate("a"); e != 15 {
+			tt.Errorf("estimate = %d, expect 15", e)
+		}
+		s3 := NewCountMinSketch[string](0.1, 0.01, WithHasher(s1.Hasher()))
+		if err := s1.Merge(s3); errors.Is(err, ErrIncompatible) != true {
+			tt.Errorf("expect ErrIncompatible: %+v", err)
+		}
+	})
+}
+
+func TestHeavyHitters(t *testing.T) {
+	a := NewArena(1024 * 1024)
+	defer a.Release()
+	h := NewHeavyHitters[int](a, 3, 0.001, 0.01)
+
+	for i := 0; i < 1000; i += 1 {
+		h.Add(i, 1)
+	}
+	h.Add(7, 5000)
+	h.Add(42, 3000)
+	h.Add(99, 1000)
+	for i := 1000; i < 2000; i += 1 {
+		h.Add(i, 1)
+	}
+
+	top := h.Top()
+	if len(top) != 3 {
+		t.Fatalf("top = %v", top)
+	}
+	expect := []int{7, 42, 99}
+	for i, k := range expect {
+		if top[i].Key != k {
+			t.Errorf("top[%d] = %v, expect key %d", i, top[i], k)
+		}
+	}
+	if e := h.Estimate(7); e < 5001 {
+		t.Errorf("estimate = %d", e)
+	}
+}
+
+func TestHeavyHittersOptions(t *testing.T) {
+	a := NewArena(1024 * 1024)
+	defer a.Release()
+
+	funcs := make([]OptionFunc, 1, 2)
+	funcs[0] = WithCapacity(1024)
+	funcs[:2][1] = WithLoadFactor(0.5)
+	NewHeavyHitters[int](a, 3, 0.001, 0.01, funcs...)
+
+	opt := newOption()
+	for _, fn := range funcs[:2] {
+		fn(opt)
+	}
+	if opt.capacity != 1024 {
+		t.Errorf("caller options must not be overwritten: capacity = %d", opt.capacity)
+	}
+}
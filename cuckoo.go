@@ -0,0 +1,167 @@
+package armap
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/dolthub/maphash"
+)
+
+const (
+	cuckooBucketSize = 4
+	cuckooMaxKicks   = 500
+)
+
+var (
+	ErrCuckooFull = errors.New("armap: cuckoo filter is full")
+)
+
+type cuckooBucket [cuckooBucketSize]uint16
+
+// CuckooFilter is approximate membership set supporting deletion.
+// 16bit fingerprints are stored in buckets on unsafe storage like Map.
+type CuckooFilter[K comparable] struct {
+	hasher     maphash.Hasher[K]
+	buckets    []byte // Unsafe storage to skip GC scanning
+	numBuckets int
+	count      int
+	rnd        uint64
+}
+
+func (f *CuckooFilter[K]) getBucket(idx int) *cuckooBucket {
+	offset := uintptr(idx) * unsafe.Sizeof(cuckooBucket{})
+	return (*cuckooBucket)(unsafe.Pointer(&f.buckets[offset]))
+}
+
+func (f *CuckooFilter[K]) indexes(key K) (fp uint16, i1, i2 int) {
+	h := f.hasher.Hash(key)
+	fp = uint16(h >> 48)
+	if fp == 0 {
+		fp = 1 // 0 means empty slot
+	}
+	i1 = int(h) & (f.numBuckets - 1)
+	i2 = f.altIndex(i1, fp)
+	return
+}
+
+func (f *CuckooFilter[K]) altIndex(idx int, fp uint16) int {
+	return (idx ^ int(uint32(fp)*0x5bd1e995)) & (f.numBuckets - 1)
+}
+
+func (f *CuckooFilter[K]) insert(idx int, fp uint16) bool {
+	b := f.getBucket(idx)
+	for i := range b {
+		if b[i] == 0 {
+			b[i] = fp
+			return true
+		}
+	}
+	return false
+}
+
+func (f *CuckooFilter[K]) Len() int {
+	return f.count
+}
+
+// LoadFactor returns the ratio of used fingerprint slots
+func (f *CuckooFilter[K]) LoadFactor() float64 {
+	return float64(f.count) / float64(f.numBuckets*cuckooBucketSize)
+}
+
+// Add inserts key, returns ErrCuckooFull when no slot is found after max kicks.
+// a failed Add undoes its kicks, so key is not added and the filter is left unchanged.
+func (f *CuckooFilter[K]) Add(key K) error {
+	fp, i1, i2 := f.indexes(key)
+	if f.insert(i1, fp) || f.insert(i2, fp) {
+		f.count += 1
+		return nil
+	}
+
+	idx := i1
+	if f.nextRand()&1 == 0 {
+		idx = i2
+	}
+	type kick struct {
+		idx  int
+		slot int
+	}
+	var kicks [cuckooMaxKicks]kick
+	for n := 0; n < cuckooMaxKicks; n += 1 {
+		b := f.getBucket(idx)
+		slot := int(f.nextRand() % cuckooBucketSize)
+		fp, b[slot] = b[slot], fp
+		kicks[n] = kick{idx, slot}
+		idx = f.altIndex(idx, fp)
+		if f.insert(idx, fp) {
+			f.count += 1
+			return nil
+		}
+	}
+	// put evicted fingerprints back in reverse order
+	for n := cuckooMaxKicks - 1; 0 <= n; n -= 1 {
+		b := f.getBucket(kicks[n].idx)
+		fp, b[kicks[n].slot] = b[kicks[n].slot], fp
+	}
+	return ErrCuckooFull
+}
+
+func (f *CuckooFilter[K]) Contains(key K) bool {
+	fp, i1, i2 := f.indexes(key)
+	b1, b2 := f.getBucket(i1), f.getBucket(i2)
+	for i := 0; i < cuckooBucketSize; i += 1 {
+		if b1[i] == fp || b2[i] == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// Delete removes one fingerprint of key, only keys that were added should be deleted
+func (f *CuckooFilter[K]) Delete(key K) bool {
+	fp, i1, i2 := f.indexes(key)
+	for _, idx := range [2]int{i1, i2} {
+		b := f.getBucket(idx)
+		for i := range b {
+			if b[i] == fp {
+				b[i] = 0
+				f.count -= 1
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *CuckooFilter[K]) Clear() {
+	clear(f.buckets)
+	f.count = 0
+}
+
+func (f *CuckooFilter[K]) nextRand() uint64 {
+	// xorshift64
+	f.rnd ^= f.rnd << 13
+	f.rnd ^= f.rnd >> 7
+	f.rnd ^= f.rnd << 17
+	return f.rnd
+}
+
+// NewCuckooFilter creates CuckooFilter that holds about capacity keys.
+func NewCuckooFilter[K comparable](capacity int, funcs ...OptionFunc) *CuckooFilter[K] {
+	opt := newOption()
+	for _, fn := range funcs {
+		fn(opt)
+	}
+
+	numBuckets := 1
+	for float64(numBuckets*cuckooBucketSize)*0.95 < float64(capacity) {
+		numBuckets *= 2
+	}
+
+	return &CuckooFilter[K]{
+		hasher:     newHasher[K](opt),
+		buckets:    make([]byte, uintptr(numBuckets)*unsafe.Sizeof(cuckooBucket{})),
+		numBuckets: numBuckets,
+		count:      0,
+		rnd:        0x9e3779b97f4a7c15,
+	}
+}
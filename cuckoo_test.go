@@ -0,0 +1,83 @@
+package armap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCuckooFilter(t *testing.T) {
+	t.Run("Add/Delete", func(tt *testing.T) {
+		N := 10_000
+		f := NewCuckooFilter[int](N)
+		for i := 0; i < N; i += 1 {
+			if err := f.Add(i); err != nil {
+				tt.Fatalf("unexpected error at %d: %+v", i, err)
+			}
+		}
+		if f.Len() != N {
+			tt.Errorf("len = %d, expect %d", f.Len(), N)
+		}
+		if lf := f.LoadFactor(); lf <= 0 || 1 < lf {
+			tt.Errorf("load factor = %f", lf)
+		}
+		for i := 0; i < N; i += 1 {
+			if f.Contains(i) != true {
+				tt.Errorf("key %d is added", i)
+			}
+		}
+
+		for i := 0; i < N; i += 2 {
+			if f.Delete(i) != true {
+				tt.Errorf("key %d is added", i)
+			}
+		}
+		for i := 1; i < N; i += 2 {
+			if f.Contains(i) != true {
+				tt.Errorf("key %d is not deleted", i)
+			}
+		}
+		fp := 0
+		for i := 0; i < N; i += 2 {
+			if f.Contains(i) {
+				fp += 1
+			}
+		}
+		if rate := float64(fp) / float64(N/2); 0.01 < rate {
+			tt.Errorf("false positive rate = %f", rate)
+		}
+	})
+
+	t.Run("full", func(tt *testing.T) {
+		f := NewCuckooFilter[int](8)
+		var err error
+		n := 0
+		for ; n < 1000; n += 1 {
+			if err = f.Add(n); err != nil {
+				break
+			}
+		}
+		if errors.Is(err, ErrCuckooFull) != true {
+			tt.Fatalf("expect ErrCuckooFull: %+v", err)
+		}
+		// failed key is not added, added keys are kept
+		if f.Len() != n {
+			tt.Errorf("len = %d, expect %d", f.Len(), n)
+		}
+		for i := 0; i < n; i += 1 {
+			if f.Contains(i) != true {
+				tt.Errorf("key %d is added", i)
+			}
+		}
+		for i := 0; i < n; i += 1 {
+			if f.Delete(i) != true {
+				tt.Errorf("key %d is added", i)
+			}
+		}
+		if f.Len() != 0 {
+			tt.Errorf("len = %d, expect 0", f.Len())
+		}
+		if err := f.Add(-1); err != nil {
+			tt.Errorf("unexpected error: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,194 @@
+package armap
+
+import (
+	"iter"
+	"unsafe"
+)
+
+// ringBuffer is circular buffer on unsafe storage shared by Deque and Ring
+type ringBuffer[T any] struct {
+	ta       TypeArena[T]
+	buf      []byte // Unsafe storage to skip GC scanning
+	elemSize uintptr
+	head     int
+	length   int
+	capacity int
+}
+
+func (r *ringBuffer[T]) init(ta TypeArena[T], capacity int) {
+	var t T
+	r.ta = ta
+	r.elemSize = unsafe.Sizeof(t)
+	r.capacity = capacity
+	r.buf = make([]byte, max(uintptr(capacity)*r.elemSize, 1))
+	r.head = 0
+	r.length = 0
+}
+
+// at returns the element at logical position i
+func (r *ringBuffer[T]) at(i int) *T {
+	offset := uintptr((r.head+i)%r.capacity) * r.elemSize
+	return (*T)(unsafe.Pointer(&r.buf[offset]))
+}
+
+func (r *ringBuffer[T]) pushBack(v T) {
+	*r.at(r.length) = r.ta.Clone(v)
+	r.length += 1
+}
+
+func (r *ringBuffer[T]) pushFront(v T) {
+	r.head = (r.head + r.capacity - 1) % r.capacity
+	*r.at(0) = r.ta.Clone(v)
+	r.length += 1
+}
+
+func (r *ringBuffer[T]) Len() int {
+	return r.length
+}
+
+func (r *ringBuffer[T]) PopFront() (v T, ok bool) {
+	if r.length == 0 {
+		return
+	}
+	p := r.at(0)
+	v = *p
+	var zero T
+	*p = zero
+	r.head = (r.head + 1) % r.capacity
+	r.length -= 1
+	return v, true
+}
+
+func (r *ringBuffer[T]) PopBack() (v T, ok bool) {
+	if r.length == 0 {
+		return
+	}
+	p := r.at(r.length - 1)
+	v = *p
+	var zero T
+	*p = zero
+	r.length -= 1
+	return v, true
+}
+
+func (r *ringBuffer[T]) PeekFront() (v T, ok bool) {
+	if r.length == 0 {
+		return
+	}
+	return *r.at(0), true
+}
+
+func (r *ringBuffer[T]) PeekBack() (v T, ok bool) {
+	if r.length == 0 {
+		return
+	}
+	return *r.at(r.length - 1), true
+}
+
+// All iterates elements from front to back
+func (r *ringBuffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < r.length; i += 1 {
+			if yield(*r.at(i)) != true {
+				return
+			}
+		}
+	}
+}
+
+func (r *ringBuffer[T]) Clear() {
+	clear(r.buf)
+	r.head = 0
+	r.length = 0
+}
+
+// Deque is double-ended queue whose storage is not scanned by GC.
+type Deque[T any] struct {
+	ringBuffer[T]
+}
+
+func (d *Deque[T]) grow() {
+	if d.length < d.capacity {
+		return
+	}
+	old := d.ringBuffer
+	d.init(old.ta, old.capacity*2)
+	for i := 0; i < old.length; i += 1 {
+		*d.at(i) = *old.at(i)
+	}
+	d.length = old.length
+}
+
+func (d *Deque[T]) PushBack(v T) {
+	d.grow()
+	d.pushBack(v)
+}
+
+func (d *Deque[T]) PushFront(v T) {
+	d.grow()
+	d.pushFront(v)
+}
+
+func NewDeque[T any](arena Arena, funcs ...OptionFunc) *Deque[T] {
+	checkType[T](arena)
+
+	opt := newOption()
+	for _, fn := range funcs {
+		fn(opt)
+	}
+
+	d := &Deque[T]{}
+	d.init(NewTypeArena[T](arena), max(opt.capacity, 1))
+	return d
+}
+
+// Ring is fixed capacity Deque.
+// when full, Push fails with ErrFull, or drops the element at the opposite end with WithOverwrite.
+type Ring[T any] struct {
+	ringBuffer[T]
+	overwrite bool
+}
+
+func (r *Ring[T]) Cap() int {
+	return r.capacity
+}
+
+func (r *Ring[T]) Full() bool {
+	return r.length == r.capacity
+}
+
+func (r *Ring[T]) PushBack(v T) error {
+	if r.Full() {
+		if r.overwrite != true {
+			return ErrFull
+		}
+		r.PopFront()
+	}
+	r.pushBack(v)
+	return nil
+}
+
+func (r *Ring[T]) PushFront(v T) error {
+	if r.Full() {
+		if r.overwrite != true {
+			return ErrFull
+		}
+		r.PopBack()
+	}
+	r.pushFront(v)
+	return nil
+}
+
+// NewRing creates Ring, WithCapacity sets the fixed capacity.
+func NewRing[T any](arena Arena, funcs ...OptionFunc) *Ring[T] {
+	checkType[T](arena)
+
+	opt := newOption()
+	for _, fn := range funcs {
+		fn(opt)
+	}
+
+	r := &Ring[T]{overwrite: opt.overwrite}
+	r.init(NewTypeArena[T](arena), max(opt.capacity, 1))
+	return r
+}
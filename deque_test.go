@@ -0,0 +1,95 @@
+package armap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	a := NewArena(1024 * 1024)
+	defer a.Release()
+	d := NewDeque[int](a, WithCapacity(4))
+
+	for i := 0; i < 10; i += 1 {
+		d.PushBack(i)
+		d.PushFront(-i)
+	}
+	if d.Len() != 20 {
+		t.Errorf("len = %d, expect 20", d.Len())
+	}
+	if v, ok := d.PeekFront(); ok != true || v != -9 {
+		t.Errorf("front = %d", v)
+	}
+	if v, ok := d.PeekBack(); ok != true || v != 9 {
+		t.Errorf("back = %d", v)
+	}
+
+	all := slices.Collect(d.All())
+	expect := []int{-9, -8, -7, -6, -5, -4, -3, -2, -1, 0, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if slices.Equal(all, expect) != true {
+		t.Errorf("all = %v", all)
+	}
+
+	for i := 9; 0 <= i; i -= 1 {
+		if v, ok := d.PopBack(); ok != true || v != i {
+			t.Errorf("pop back = %d, expect %d", v, i)
+		}
+		if v, ok := d.PopFront(); ok != true || v != -i {
+			t.Errorf("pop front = %d, expect %d", v, -i)
+		}
+	}
+	if _, ok := d.PopFront(); ok {
+		t.Errorf("empty deque")
+	}
+}
+
+func TestRing(t *testing.T) {
+	t.Run("full", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		r := NewRing[string](a, WithCapacity(3))
+
+		for _, s := range []string{"a", "b", "c"} {
+			if err := r.PushBack(s); err != nil {
+				tt.Errorf("unexpected error: %+v", err)
+			}
+		}
+		if r.Full() != true {
+			tt.Errorf("expect full")
+		}
+		if err := r.PushBack("d"); errors.Is(err, ErrFull) != true {
+			tt.Errorf("expect ErrFull: %+v", err)
+		}
+		if v, _ := r.PopFront(); v != "a" {
+			tt.Errorf("pop front = %s", v)
+		}
+		if err := r.PushFront("z"); err != nil {
+			tt.Errorf("unexpected error: %+v", err)
+		}
+		if all := slices.Collect(r.All()); slices.Equal(all, []string{"z", "b", "c"}) != true {
+			tt.Errorf("all = %v", all)
+		}
+	})
+
+	t.Run("WithOverwrite", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		r := NewRing[int](a, WithCapacity(3), WithOverwrite(true))
+
+		for i := 0; i < 10; i += 1 {
+			if err := r.PushBack(i); err != nil {
+				tt.Errorf("unexpected error: %+v", err)
+			}
+		}
+		if r.Len() != 3 || r.Cap() != 3 {
+			tt.Errorf("len = %d cap = %d", r.Len(), r.Cap())
+		}
+		if all := slices.Collect(r.All()); slices.Equal(all, []int{7, 8, 9}) != true {
+			tt.Errorf("all = %v", all)
+		}
+		if v, ok := r.PeekFront(); ok != true || v != 7 {
+			tt.Errorf("front = %d", v)
+		}
+	})
+}
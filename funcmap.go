@@ -0,0 +1,216 @@
+package armap
+
+import (
+	"unsafe"
+)
+
+type HashFunc[K any] func(K) uint64
+type EqualFunc[K any] func(K, K) bool
+
+type funcBucket[K any, V any] struct {
+	key   K
+	value V
+	hash  uint64
+	state bucketState
+}
+
+// FuncMap is Map that uses user-supplied hash and equal functions instead of maphash and ==,
+// for keys that are not comparable or whose == is not the desired equality.
+// WithCapacity and WithLoadFactor are supported, other options are ignored.
+type FuncMap[K any, V any] struct {
+	arena      Arena
+	hash       HashFunc[K]
+	equal      EqualFunc[K]
+	buckets    []byte // Unsafe storage to skip GC scanning
+	bucketSize uintptr
+	count      int
+	capacity   int
+	loadFactor float64
+}
+
+func (m *FuncMap[K, V]) getBucket(idx int) *funcBucket[K, V] {
+	offset := uintptr(idx) * m.bucketSize
+	return (*funcBucket[K, V])(unsafe.Pointer(&m.buckets[offset]))
+}
+
+func (m *FuncMap[K, V]) Len() int {
+	return m.count
+}
+
+func (m *FuncMap[K, V]) indexHash(hash uint64) int {
+	return int(hash) & (m.capacity - 1)
+}
+
+func (m *FuncMap[K, V]) Set(key K, value V) (old V, found bool) {
+	if m.loadFactor < (float64(m.count) / float64(m.capacity)) {
+		m.resize(m.capacity * 2)
+	}
+
+	hash := m.hash(key)
+	idx := m.indexHash(hash)
+	startIdx := idx
+
+	ka := NewTypeArena[K](m.arena)
+	va := NewTypeArena[V](m.arena)
+	for {
+		b := m.getBucket(idx)
+		if b.state == stateEmpty {
+			b.key = ka.Clone(key)
+			b.value = va.Clone(value)
+			b.hash = hash
+			b.state = stateUsed
+			m.count += 1
+			return
+		}
+		if b.hash == hash && m.equal(b.key, key) {
+			old = b.value
+			found = true
+			b.value = va.Clone(value)
+			return
+		}
+
+		idx = (idx + 1) & (m.capacity - 1)
+		if idx == startIdx {
+			m.resize(m.capacity * 2)
+			idx = m.indexHash(hash)
+			startIdx = idx
+		}
+	}
+}
+
+func (m *FuncMap[K, V]) Get(key K) (val V, found bool) {
+	if idx, ok := m.find(key); ok {
+		return m.getBucket(idx).value, true
+	}
+	return
+}
+
+func (m *FuncMap[K, V]) find(key K) (int, bool) {
+	if m.capacity == 0 {
+		return -1, false
+	}
+	hash := m.hash(key)
+	idx := m.indexHash(hash)
+	startIdx := idx
+
+	for {
+		b := m.getBucket(idx)
+		if b.state == stateEmpty {
+			return -1, false
+		}
+		if b.hash == hash && m.equal(b.key, key) {
+			return idx, true
+		}
+		idx = (idx + 1) & (m.capacity - 1)
+		if idx == startIdx {
+			return -1, false
+		}
+	}
+}
+
+func (m *FuncMap[K, V]) Scan(iter func(K, V) bool) {
+	for i := 0; i < m.capacity; i += 1 {
+		b := m.getBucket(i)
+		if b.state == stateUsed {
+			if iter(b.key, b.value) != true {
+				return
+			}
+		}
+	}
+}
+
+func (m *FuncMap[K, V]) Delete(key K) (old V, found bool) {
+	idx, ok := m.find(key)
+	if ok != true {
+		return
+	}
+	old = m.getBucket(idx).value
+	found = true
+	m.count -= 1
+	m.shiftBack(idx)
+	return
+}
+
+// shiftBack fills the hole at idx by moving back entries of the same cluster (Knuth's algorithm)
+func (m *FuncMap[K, V]) shiftBack(idx int) {
+	mask := m.capacity - 1
+	curr := idx
+	scan := (curr + 1) & mask
+	for {
+		b := m.getBucket(scan)
+		if b.state == stateEmpty {
+			var zero funcBucket[K, V]
+			*m.getBucket(curr) = zero
+			return
+		}
+
+		// entry stays if its ideal position lies in cyclic interval (curr, scan]
+		ideal := m.indexHash(b.hash)
+		stay := false
+		if curr < scan {
+			stay = curr < ideal && ideal <= scan
+		} else {
+			stay = curr < ideal || ideal <= scan
+		}
+		if stay != true {
+			*m.getBucket(curr) = *b
+			curr = scan
+		}
+		scan = (scan + 1) & mask
+	}
+}
+
+func (m *FuncMap[K, V]) resize(newCapacity int) {
+	oldBuckets := m.buckets
+	oldCapacity := m.capacity
+
+	var b funcBucket[K, V]
+	m.bucketSize = unsafe.Sizeof(b)
+	m.capacity = newCapacity
+	m.buckets = make([]byte, uintptr(newCapacity)*m.bucketSize)
+
+	for i := 0; i < oldCapacity; i += 1 {
+		ob := (*funcBucket[K, V])(unsafe.Pointer(&oldBuckets[uintptr(i)*m.bucketSize]))
+		if ob.state != stateUsed {
+			continue
+		}
+		idx := m.indexHash(ob.hash)
+		for m.getBucket(idx).state != stateEmpty {
+			idx = (idx + 1) & (m.capacity - 1)
+		}
+		*m.getBucket(idx) = *ob
+	}
+}
+
+func (m *FuncMap[K, V]) Clear() {
+	m.buckets = make([]byte, uintptr(m.capacity)*m.bucketSize)
+	m.count = 0
+}
+
+func NewFuncMap[K any, V any](arena Arena, hash HashFunc[K], equal EqualFunc[K], funcs ...OptionFunc) *FuncMap[K, V] {
+	checkType[K](arena)
+	checkType[V](arena)
+
+	opt := newOption()
+	for _, fn := range funcs {
+		fn(opt)
+	}
+	if opt.fixed {
+		panic("armap: FuncMap does not support WithFixedCapacity")
+	}
+
+	capacity := 1
+	for capacity < opt.capacity {
+		capacity *= 2
+	}
+
+	m := &FuncMap[K, V]{
+		arena:      arena,
+		hash:       hash,
+		equal:      equal,
+		capacity:   0,
+		loadFactor: opt.loadFactor,
+	}
+	m.resize(capacity)
+	return m
+}
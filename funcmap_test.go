@@ -0,0 +1,102 @@
+package armap
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFuncMap(t *testing.T) {
+	t.Run("case_insensitive", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewFuncMap[string, int](a,
+			func(k string) uint64 {
+				h := fnv.New64a()
+				h.Write([]byte(strings.ToLower(k)))
+				return h.Sum64()
+			},
+			strings.EqualFold,
+		)
+
+		m.Set("Hello", 1)
+		if v, ok := m.Get("HELLO"); ok != true || v != 1 {
+			tt.Errorf("HELLO value = %d", v)
+		}
+		if old, ok := m.Set("hello", 2); ok != true || old != 1 {
+			tt.Errorf("hello old = %d", old)
+		}
+		if m.Len() != 1 {
+			tt.Errorf("len = %d, expect 1", m.Len())
+		}
+		if old, ok := m.Delete("hElLo"); ok != true || old != 2 {
+			tt.Errorf("hElLo old = %d", old)
+		}
+		if m.Len() != 0 {
+			tt.Errorf("len = %d, expect 0", m.Len())
+		}
+	})
+
+	t.Run("slice_key", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewFuncMap[[]int, int](a,
+			func(k []int) uint64 {
+				h := uint64(17)
+				for _, v := range k {
+					h = h*31 + uint64(v)
+				}
+				return h * 0x9e3779b97f4a7c15
+			},
+			slices.Equal[[]int],
+			WithCapacity(8),
+		)
+
+		expect := make(map[[2]int]int)
+		r := rand.New(rand.NewPCG(1, 2))
+		for i := 0; i < 5000; i += 1 {
+			k := [2]int{r.IntN(100), r.IntN(100)}
+			if r.IntN(3) == 0 {
+				_, ok := m.Delete(k[:])
+				_, exists := expect[k]
+				if ok != exists {
+					tt.Errorf("delete %v = %v, expect %v", k, ok, exists)
+				}
+				delete(expect, k)
+				continue
+			}
+			m.Set(k[:], i)
+			expect[k] = i
+		}
+
+		if m.Len() != len(expect) {
+			tt.Errorf("len = %d, expect %d", m.Len(), len(expect))
+		}
+		for k, v := range expect {
+			if actual, ok := m.Get(k[:]); ok != true || actual != v {
+				tt.Errorf("key %v value = %d, expect %d", k, actual, v)
+			}
+		}
+		count := 0
+		m.Scan(func(k []int, v int) bool {
+			count += 1
+			return true
+		})
+		if count != len(expect) {
+			tt.Errorf("scan count = %d, expect %d", count, len(expect))
+		}
+	})
+}
+
+func TestFuncMapFixedCapacity(t *testing.T) {
+	a := NewArena(1024 * 1024)
+	defer a.Release()
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("WithFixedCapacity must panic")
+		}
+	}()
+	NewFuncMap[string, int](a, func(s string) uint64 { return uint64(len(s)) }, func(a, b string) bool { return a == b }, WithFixedCapacity(4))
+}
@@ -0,0 +1,337 @@
+package armap
+
+import (
+	"unsafe"
+)
+
+// HeapHandle refers to a pushed element, the slot is in the lower 32 bits and the generation in the upper 32 bits.
+// the generation makes a handle invalid once its element is popped or removed, even when the slot is reused.
+type HeapHandle uint64
+
+func newHeapHandle(slot int, gen uint32) HeapHandle {
+	return HeapHandle(uint64(gen)<<32 | uint64(uint32(slot)))
+}
+
+func (h HeapHandle) slot() int {
+	return int(uint32(h))
+}
+
+func (h HeapHandle) gen() uint32 {
+	return uint32(h >> 32)
+}
+
+type heapNode[T any] struct {
+	value T
+	slot  int
+}
+
+// Heap is binary min-heap ordered by less whose storage is not scanned by GC.
+// Push returns a handle that stays valid until the element is popped or removed.
+type Heap[T any] struct {
+	ta       TypeArena[T]
+	less     func(T, T) bool
+	nodes    []byte // Unsafe storage to skip GC scanning
+	nodeSize uintptr
+	length   int
+	capacity int
+	fixed    bool
+	pos      []int    // slot -> node index, -1 if free
+	gens     []uint32 // slot -> current generation, starts at 1 so that zero HeapHandle is never valid
+	free     []int
+}
+
+func (h *Heap[T]) node(i int) *heapNode[T] {
+	return (*heapNode[T])(unsafe.Pointer(&h.nodes[uintptr(i)*h.nodeSize]))
+}
+
+func (h *Heap[T]) Len() int {
+	return h.length
+}
+
+func (h *Heap[T]) grow() {
+	if h.length < h.capacity {
+		return
+	}
+	nodes := make([]byte, uintptr(h.capacity*2)*h.nodeSize)
+	copy(nodes, h.nodes)
+	h.nodes = nodes
+	h.capacity *= 2
+}
+
+func (h *Heap[T]) newHandle(i int) HeapHandle {
+	if n := len(h.free); 0 < n {
+		slot := h.free[n-1]
+		h.free = h.free[:n-1]
+		h.pos[slot] = i
+		return newHeapHandle(slot, h.gens[slot])
+	}
+	h.pos = append(h.pos, i)
+	h.gens = append(h.gens, 1)
+	return newHeapHandle(len(h.pos)-1, 1)
+}
+
+// freeSlot invalidates all handles of slot and makes it reusable
+func (h *Heap[T]) freeSlot(slot int) {
+	h.pos[slot] = -1
+	h.gens[slot] += 1
+	if h.gens[slot] == 0 {
+		h.gens[slot] = 1
+	}
+	h.free = append(h.free, slot)
+}
+
+// Push inserts value and returns its handle.
+// the value is not inserted and an invalid handle is returned when the Heap created by WithFixedCapacity is full;
+// use TryPush to detect it.
+func (h *Heap[T]) Push(value T) HeapHandle {
+	handle, _ := h.TryPush(value)
+	return handle
+}
+
+// TryPush is Push that returns ErrFull when the Heap created by WithFixedCapacity is full
+func (h *Heap[T]) TryPush(value T) (HeapHandle, error) {
+	if h.fixed && h.capacity <= h.length {
+		return 0, ErrFull
+	}
+	h.grow()
+	i := h.length
+	handle := h.newHandle(i)
+	n := h.node(i)
+	n.value = h.ta.Clone(value)
+	n.slot = handle.slot()
+	h.length += 1
+	h.up(i)
+	return handle, nil
+}
+
+func (h *Heap[T]) Peek() (v T, ok bool) {
+	if h.length == 0 {
+		return
+	}
+	return h.node(0).value, true
+}
+
+func (h *Heap[T]) Pop() (v T, ok bool) {
+	if h.length == 0 {
+		return
+	}
+	return h.removeAt(0), true
+}
+
+func (h *Heap[T]) valid(handle HeapHandle) bool {
+	slot := handle.slot()
+	if len(h.pos) <= slot {
+		return false
+	}
+	return 0 <= h.pos[slot] && h.gens[slot] == handle.gen()
+}
+
+// Get returns the value of handle
+func (h *Heap[T]) Get(handle HeapHandle) (v T, ok bool) {
+	if h.valid(handle) != true {
+		return
+	}
+	return h.node(h.pos[handle.slot()]).value, true
+}
+
+// Fix replaces the value of handle and restores the heap order
+func (h *Heap[T]) Fix(handle HeapHandle, value T) bool {
+	if h.valid(handle) != true {
+		return false
+	}
+	i := h.pos[handle.slot()]
+	h.node(i).value = h.ta.Clone(value)
+	if h.down(i) != true {
+		h.up(i)
+	}
+	return true
+}
+
+func (h *Heap[T]) Remove(handle HeapHandle) (v T, ok bool) {
+	if h.valid(handle) != true {
+		return
+	}
+	return h.removeAt(h.pos[handle.slot()]), true
+}
+
+func (h *Heap[T]) removeAt(i int) T {
+	last := h.length - 1
+	if i != last {
+		h.swap(i, last)
+	}
+	n := h.node(last)
+	value := n.value
+	h.freeSlot(n.slot)
+	*n = heapNode[T]{}
+	h.length -= 1
+
+	if i != last {
+		if h.down(i) != true {
+			h.up(i)
+		}
+	}
+	return value
+}
+
+func (h *Heap[T]) swap(i, j int) {
+	a, b := h.node(i), h.node(j)
+	*a, *b = *b, *a
+	h.pos[a.slot] = i
+	h.pos[b.slot] = j
+}
+
+func (h *Heap[T]) up(i int) {
+	for 0 < i {
+		parent := (i - 1) / 2
+		if h.less(h.node(i).value, h.node(parent).value) != true {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+// down reports whether the element at i moved
+func (h *Heap[T]) down(i int) bool {
+	start := i
+	for {
+		left := 2*i + 1
+		if h.length <= left {
+			break
+		}
+		child := left
+		if right := left + 1; right < h.length && h.less(h.node(right).value, h.node(left).value) {
+			child = right
+		}
+		if h.less(h.node(child).value, h.node(i).value) != true {
+			break
+		}
+		h.swap(i, child)
+		i = child
+	}
+	return start < i
+}
+
+func (h *Heap[T]) Clear() {
+	for i := 0; i < h.length; i += 1 {
+		h.freeSlot(h.node(i).slot)
+	}
+	clear(h.nodes)
+	h.length = 0
+}
+
+func NewHeap[T any](arena Arena, less func(T, T) bool, funcs ...OptionFunc) *Heap[T] {
+	checkType[T](arena)
+
+	opt := newOption()
+	for _, fn := range funcs {
+		fn(opt)
+	}
+
+	var n heapNode[T]
+	capacity := max(opt.capacity, 1)
+	return &Heap[T]{
+		ta:       NewTypeArena[T](arena),
+		less:     less,
+		nodes:    make([]byte, uintptr(capacity)*unsafe.Sizeof(n)),
+		nodeSize: unsafe.Sizeof(n),
+		length:   0,
+		capacity: capacity,
+		fixed:    opt.fixed,
+		pos:      make([]int, 0, capacity),
+		gens:     make([]uint32, 0, capacity),
+		free:     nil,
+	}
+}
+
+// fields are exported so that TypeArena can clone the entry
+type indexedEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// IndexedHeap is Heap keyed by K, supporting decrease-key through Map lookup.
+type IndexedHeap[K comparable, V any] struct {
+	h       *Heap[indexedEntry[K, V]]
+	handles *Map[K, HeapHandle]
+}
+
+func (ih *IndexedHeap[K, V]) Len() int {
+	return ih.h.Len()
+}
+
+// Push inserts key or updates its value (decrease/increase key).
+// a new key is not inserted when the IndexedHeap created by WithFixedCapacity is full; use TryPush to detect it.
+func (ih *IndexedHeap[K, V]) Push(key K, value V) {
+	ih.TryPush(key, value)
+}
+
+// TryPush is Push that returns ErrFull when key is new and the IndexedHeap created by WithFixedCapacity is full
+func (ih *IndexedHeap[K, V]) TryPush(key K, value V) error {
+	if handle, ok := ih.handles.Get(key); ok {
+		ih.h.Fix(handle, indexedEntry[K, V]{key, value})
+		return nil
+	}
+	handle, err := ih.h.TryPush(indexedEntry[K, V]{key, value})
+	if err != nil {
+		return err
+	}
+	if _, _, err := ih.handles.TrySet(key, handle); err != nil {
+		ih.h.Remove(handle)
+		return err
+	}
+	return nil
+}
+
+func (ih *IndexedHeap[K, V]) Peek() (key K, value V, ok bool) {
+	e, ok := ih.h.Peek()
+	return e.Key, e.Value, ok
+}
+
+func (ih *IndexedHeap[K, V]) Pop() (key K, value V, ok bool) {
+	e, ok := ih.h.Pop()
+	if ok {
+		ih.handles.Delete(e.Key)
+	}
+	return e.Key, e.Value, ok
+}
+
+func (ih *IndexedHeap[K, V]) Get(key K) (value V, ok bool) {
+	handle, ok := ih.handles.Get(key)
+	if ok != true {
+		return
+	}
+	e, _ := ih.h.Get(handle)
+	return e.Value, true
+}
+
+func (ih *IndexedHeap[K, V]) Remove(key K) (value V, ok bool) {
+	handle, ok := ih.handles.Delete(key)
+	if ok != true {
+		return
+	}
+	e, _ := ih.h.Remove(handle)
+	return e.Value, true
+}
+
+// scan calls fn for every key in unspecified order
+func (ih *IndexedHeap[K, V]) scan(fn func(K, V) bool) {
+	ih.handles.Scan(func(key K, handle HeapHandle) bool {
+		e, _ := ih.h.Get(handle)
+		return fn(key, e.Value)
+	})
+}
+
+func (ih *IndexedHeap[K, V]) Clear() {
+	ih.h.Clear()
+	ih.handles.Clear()
+}
+
+func NewIndexedHeap[K comparable, V any](arena Arena, less func(V, V) bool, funcs ...OptionFunc) *IndexedHeap[K, V] {
+	return &IndexedHeap[K, V]{
+		h: NewHeap[indexedEntry[K, V]](arena, func(a, b indexedEntry[K, V]) bool {
+			return less(a.Value, b.Value)
+		}, funcs...),
+		handles: NewMap[K, HeapHandle](arena, funcs...),
+	}
+}
@@ -0,0 +1,199 @@
+package armap
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestHeap(t *testing.T) {
+	t.Run("Push/Pop", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		h := NewHeap[int](a, func(a, b int) bool { return a < b }, WithCapacity(4))
+
+		r := rand.New(rand.NewPCG(1, 2))
+		values := make([]int, 1000)
+		for i := range values {
+			values[i] = r.IntN(10_000)
+			h.Push(values[i])
+		}
+		slices.Sort(values)
+
+		if v, ok := h.Peek(); ok != true || v != values[0] {
+			tt.Errorf("peek = %d, expect %d", v, values[0])
+		}
+		for _, expect := range values {
+			if v, ok := h.Pop(); ok != true || v != expect {
+				tt.Errorf("pop = %d, expect %d", v, expect)
+			}
+		}
+		if _, ok := h.Pop(); ok {
+			tt.Errorf("empty heap")
+		}
+	})
+
+	t.Run("stale_handle", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		h := NewHeap[int](a, func(a, b int) bool { return a < b })
+
+		old := h.Push(5)
+		h.Pop()
+		h.Push(7) // reuses the slot of old
+		if h.Fix(old, 1) {
+			tt.Errorf("popped handle must be invalid")
+		}
+		if _, ok := h.Get(old); ok {
+			tt.Errorf("popped handle must be invalid")
+		}
+		if _, ok := h.Remove(old); ok {
+			tt.Errorf("popped handle must be invalid")
+		}
+		if v, _ := h.Peek(); v != 7 {
+			tt.Errorf("peek = %d, expect 7", v)
+		}
+
+		cleared := h.Push(3)
+		h.Clear()
+		h.Push(9)
+		if _, ok := h.Get(cleared); ok {
+			tt.Errorf("cleared handle must be invalid")
+		}
+		if _, ok := h.Get(HeapHandle(0)); ok {
+			tt.Errorf("zero handle must be invalid")
+		}
+	})
+
+	t.Run("Fix/Remove", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		h := NewHeap[string](a, func(a, b string) bool { return a < b })
+
+		hc := h.Push("c")
+		hd := h.Push("d")
+		h.Push("e")
+
+		if ok := h.Fix(hd, "a"); ok != true {
+			tt.Errorf("handle d is valid")
+		}
+		if v, _ := h.Peek(); v != "a" {
+			tt.Errorf("peek = %s, expect a", v)
+		}
+		if v, ok := h.Remove(hc); ok != true || v != "c" {
+			tt.Errorf("remove = %s", v)
+		}
+		if _, ok := h.Remove(hc); ok {
+			tt.Errorf("handle c is removed")
+		}
+		if v, ok := h.Get(hd); ok != true || v != "a" {
+			tt.Errorf("get = %s", v)
+		}
+
+		actual := []string{}
+		for 0 < h.Len() {
+			v, _ := h.Pop()
+			actual = append(actual, v)
+		}
+		if slices.Equal(actual, []string{"a", "e"}) != true {
+			tt.Errorf("actual = %v", actual)
+		}
+	})
+	t.Run("WithFixedCapacity", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		h := NewHeap[int](a, func(a, b int) bool { return a < b }, WithFixedCapacity(4))
+
+		for i := 0; i < 4; i += 1 {
+			if _, err := h.TryPush(i); err != nil {
+				tt.Errorf("unexpected error: %+v", err)
+			}
+		}
+		if _, err := h.TryPush(-1); errors.Is(err, ErrFull) != true {
+			tt.Errorf("err = %+v, expect ErrFull", err)
+		}
+		handle := h.Push(-1)
+		if _, ok := h.Get(handle); ok {
+			tt.Errorf("rejected handle must be invalid")
+		}
+		if h.Len() != 4 {
+			tt.Errorf("len = %d, expect 4", h.Len())
+		}
+		if v, _ := h.Pop(); v != 0 {
+			tt.Errorf("pop = %d, expect 0", v)
+		}
+		if _, err := h.TryPush(-1); err != nil {
+			tt.Errorf("unexpected error: %+v", err)
+		}
+		if v, _ := h.Peek(); v != -1 {
+			tt.Errorf("peek = %d, expect -1", v)
+		}
+	})
+}
+
+func TestIndexedHeap(t *testing.T) {
+	a := NewArena(1024 * 1024)
+	defer a.Release()
+	h := NewIndexedHeap[string, int](a, func(a, b int) bool { return a < b })
+
+	h.Push("a", 10)
+	h.Push("b", 20)
+	h.Push("c", 30)
+	h.Push("c", 5) // decrease key
+
+	if h.Len() != 3 {
+		t.Errorf("len = %d, expect 3", h.Len())
+	}
+	if k, v, ok := h.Peek(); ok != true || k != "c" || v != 5 {
+		t.Errorf("peek = %s %d", k, v)
+	}
+	if v, ok := h.Get("b"); ok != true || v != 20 {
+		t.Errorf("get b = %d", v)
+	}
+	if v, ok := h.Remove("a"); ok != true || v != 10 {
+		t.Errorf("remove a = %d", v)
+	}
+	if k, v, ok := h.Pop(); ok != true || k != "c" || v != 5 {
+		t.Errorf("pop = %s %d", k, v)
+	}
+	if k, v, ok := h.Pop(); ok != true || k != "b" || v != 20 {
+		t.Errorf("pop = %s %d", k, v)
+	}
+	if _, _, ok := h.Pop(); ok {
+		t.Errorf("empty heap")
+	}
+	if _, ok := h.Get("c"); ok {
+		t.Errorf("c is popped")
+	}
+
+	t.Run("WithFixedCapacity", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		h := NewIndexedHeap[int, int](a, func(a, b int) bool { return a < b }, WithFixedCapacity(4))
+
+		n := 0
+		for i := 0; i < 10; i += 1 {
+			if err := h.TryPush(i, i); err != nil {
+				if errors.Is(err, ErrFull) != true {
+					tt.Errorf("unexpected error: %+v", err)
+				}
+				continue
+			}
+			n += 1
+		}
+		h.Push(9, -1)
+		if h.Len() != n {
+			tt.Errorf("len = %d, expect %d", h.Len(), n)
+		}
+		for i := 0; i < n; i += 1 {
+			k, v, ok := h.Pop()
+			if ok != true || k != i || v != i {
+				tt.Errorf("pop = %d %d %v, expect %d", k, v, ok, i)
+			}
+		}
+		if h.Len() != 0 {
+			tt.Errorf("len = %d, expect 0", h.Len())
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package armap
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/dolthub/maphash"
+)
+
+// HyperLogLog estimates the number of distinct keys.
+// it starts with sparse registers and switches to dense registers as cardinality grows.
+type HyperLogLog[K comparable] struct {
+	hasher    maphash.Hasher[K]
+	precision uint8
+	m         uint32
+	sparse    map[uint32]uint8
+	dense     []uint8
+}
+
+func (h *HyperLogLog[K]) Add(key K) {
+	x := h.hasher.Hash(key)
+	idx := uint32(x >> (64 - h.precision))
+	rho := uint8(bits.LeadingZeros64((x<<h.precision)|(1<<(h.precision-1)))) + 1
+	h.set(idx, rho)
+}
+
+func (h *HyperLogLog[K]) set(idx uint32, rho uint8) {
+	if h.dense != nil {
+		h.dense[idx] = max(h.dense[idx], rho)
+		return
+	}
+	if h.sparse[idx] < rho {
+		h.sparse[idx] = rho
+	}
+	// sparse map is larger than dense registers from m/8 entries
+	if uint32(len(h.sparse)) > h.m/8 {
+		h.toDense()
+	}
+}
+
+func (h *HyperLogLog[K]) toDense() {
+	h.dense = make([]uint8, h.m)
+	for idx, rho := range h.sparse {
+		h.dense[idx] = rho
+	}
+	h.sparse = nil
+}
+
+// Count returns the estimated cardinality
+func (h *HyperLogLog[K]) Count() uint64 {
+	m := float64(h.m)
+	if h.dense == nil {
+		// linear counting is accurate while most registers are zero
+		return uint64(math.Round(m * math.Log(m/(m-float64(len(h.sparse))))))
+	}
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.dense {
+		sum += 1.0 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros += 1
+		}
+	}
+	estimate := h.alpha() * m * m / sum
+	if estimate <= 2.5*m && 0 < zeros {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(estimate))
+}
+
+func (h *HyperLogLog[K]) alpha() float64 {
+	switch h.m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(h.m))
+}
+
+// Merge merges other into h, both must have the same precision and hasher
+func (h *HyperLogLog[K]) Merge(other *HyperLogLog[K]) error {
+	if h.precision != other.precision || sameHasher(h.hasher, other.hasher) != true {
+		return ErrIncompatible
+	}
+	if other.dense == nil {
+		for idx, rho := range other.sparse {
+			h.set(idx, rho)
+		}
+		return nil
+	}
+	if h.dense == nil {
+		h.toDense()
+	}
+	for idx, rho := range other.dense {
+		h.dense[idx] = max(h.dense[idx], rho)
+	}
+	return nil
+}
+
+func (h *HyperLogLog[K]) Hasher() maphash.Hasher[K] {
+	return h.hasher
+}
+
+func (h *HyperLogLog[K]) Clear() {
+	h.sparse = make(map[uint32]uint8)
+	h.dense = nil
+}
+
+// NewHyperLogLog creates HyperLogLog with 2^precision registers, precision is clamped to [4, 18].
+// standard error is about 1.04/sqrt(2^precision).
+func NewHyperLogLog[K comparable](precision uint8, funcs ...OptionFunc) *HyperLogLog[K] {
+	opt := newOption()
+	for _, fn := range funcs {
+		fn(opt)
+	}
+
+	precision = min(max(precision, 4), 18)
+	return &HyperLogLog[K]{
+		hasher:    newHasher[K](opt),
+		precision: precision,
+		m:         uint32(1) << precision,
+		sparse:    make(map[uint32]uint8),
+		dense:     nil,
+	}
+}
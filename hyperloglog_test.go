@@ -0,0 +1,58 @@
+package armap
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	t.Run("Count", func(tt *testing.T) {
+		for _, n := range []int{1000, 100_000} {
+			h := NewHyperLogLog[int](14)
+			for i := 0; i < n; i += 1 {
+				h.Add(i)
+				h.Add(i) // duplicate
+			}
+			c := h.Count()
+			if errRate := math.Abs(float64(c)-float64(n)) / float64(n); 0.04 < errRate {
+				tt.Errorf("n=%d count = %d (error %f)", n, c, errRate)
+			}
+		}
+	})
+
+	t.Run("sparse", func(tt *testing.T) {
+		h := NewHyperLogLog[int](14)
+		for i := 0; i < 100; i += 1 {
+			h.Add(i)
+		}
+		if h.dense != nil {
+			tt.Errorf("expect sparse")
+		}
+		if c := h.Count(); c < 95 || 105 < c {
+			tt.Errorf("count = %d, expect ~100", c)
+		}
+	})
+
+	t.Run("Merge", func(tt *testing.T) {
+		h1 := NewHyperLogLog[int](12)
+		h2 := NewHyperLogLog[int](12, WithHasher(h1.Hasher()))
+		for i := 0; i < 50_000; i += 1 {
+			h1.Add(i)
+		}
+		for i := 25_000; i < 75_000; i += 1 {
+			h2.Add(i)
+		}
+		if err := h1.Merge(h2); err != nil {
+			tt.Fatalf("unexpected error: %+v", err)
+		}
+		if errRate := math.Abs(float64(h1.Count())-75_000) / 75_000; 0.07 < errRate {
+			tt.Errorf("count = %d (error %f)", h1.Count(), errRate)
+		}
+
+		h3 := NewHyperLogLog[int](10, WithHasher(h1.Hasher()))
+		if err := h1.Merge(h3); errors.Is(err, ErrIncompatible) != true {
+			tt.Errorf("expect ErrIncompatible: %+v", err)
+		}
+	})
+}
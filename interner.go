@@ -0,0 +1,99 @@
+package armap
+
+import (
+	"math"
+)
+
+// Interner deduplicates strings into canonical arena-resident copies.
+// each distinct string is also assigned a sequential uint32 handle.
+type Interner struct {
+	arena Arena
+	ids   *Map[string, uint32]
+	strs  *Vector[string] // id -> string, ids are sequential
+	bytes int
+}
+
+func (i *Interner) Len() int {
+	return i.ids.Len()
+}
+
+// Bytes returns the number of string bytes stored in the arena
+func (i *Interner) Bytes() int {
+	return i.bytes
+}
+
+// Intern returns the canonical copy of s.
+// s is returned as is when it is new and the Interner created by WithFixedCapacity is full; use TryIntern to detect it.
+func (i *Interner) Intern(s string) string {
+	str, _, err := i.intern(s)
+	if err != nil {
+		return s
+	}
+	return str
+}
+
+// TryIntern is Intern that returns ErrFull when s is new and the Interner created by WithFixedCapacity is full
+func (i *Interner) TryIntern(s string) (string, error) {
+	str, _, err := i.intern(s)
+	return str, err
+}
+
+// InternBytes is Intern for []byte, b is not retained and no allocation happens when already interned.
+// when full, a heap copy of b is returned.
+func (i *Interner) InternBytes(b []byte) string {
+	str, _, err := i.intern(bytesView(b))
+	if err != nil {
+		return string(b)
+	}
+	return str
+}
+
+// ID returns the handle of s, interning s if needed.
+// it returns math.MaxUint32, which Lookup does not know, when s is new and the Interner created by WithFixedCapacity is full.
+func (i *Interner) ID(s string) uint32 {
+	id, err := i.TryID(s)
+	if err != nil {
+		return math.MaxUint32
+	}
+	return id
+}
+
+// TryID is ID that returns ErrFull when s is new and the Interner created by WithFixedCapacity is full
+func (i *Interner) TryID(s string) (uint32, error) {
+	_, id, err := i.intern(s)
+	return id, err
+}
+
+// Lookup returns the string of handle id, or "" if id is unknown
+func (i *Interner) Lookup(id uint32) string {
+	if uint64(i.strs.Len()) <= uint64(id) {
+		return ""
+	}
+	return i.strs.At(int(id))
+}
+
+func (i *Interner) intern(s string) (string, uint32, error) {
+	h := i.ids.Hash(s)
+	if b := i.ids.find(h, s); b != nil {
+		return b.key, b.value, nil
+	}
+	if i.ids.full() {
+		return "", 0, ErrFull
+	}
+
+	str := cloneString(i.arena, s)
+	id := uint32(i.strs.Len())
+	i.ids.SetHashed(h, str, id)
+	i.strs.Append(str)
+	i.bytes += len(str)
+	return str, id, nil
+}
+
+func NewInterner(arena Arena, funcs ...OptionFunc) *Interner {
+	return &Interner{
+		arena: arena,
+		ids:   NewMap[string, uint32](arena, funcs...),
+		strs:  NewVector[string](arena, funcs...),
+		bytes: 0,
+	}
+}
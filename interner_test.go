@@ -0,0 +1,114 @@
+package armap
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"unsafe"
+)
+
+func TestInterner(t *testing.T) {
+	t.Run("Intern", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		in := NewInterner(a)
+
+		s1 := in.Intern("hostname-1")
+		s2 := in.InternBytes([]byte("hostname-1"))
+		if s1 != s2 {
+			tt.Errorf("%s != %s", s1, s2)
+		}
+		if unsafe.StringData(s1) != unsafe.StringData(s2) {
+			tt.Errorf("expect same canonical string")
+		}
+		in.Intern("hostname-2")
+
+		if in.Len() != 2 {
+			tt.Errorf("len = %d, expect 2", in.Len())
+		}
+		if in.Bytes() != 20 {
+			tt.Errorf("bytes = %d, expect 20", in.Bytes())
+		}
+	})
+
+	t.Run("ID/Lookup", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		in := NewInterner(a)
+
+		idA := in.ID("a")
+		idB := in.ID("b")
+		if idA == idB {
+			tt.Errorf("expect different handle")
+		}
+		if id := in.ID("a"); id != idA {
+			tt.Errorf("id = %d, expect %d", id, idA)
+		}
+		if s := in.Lookup(idB); s != "b" {
+			tt.Errorf("lookup = %s, expect b", s)
+		}
+		if s := in.Lookup(100); s != "" {
+			tt.Errorf("lookup unknown = %s", s)
+		}
+		if s := in.Lookup(math.MaxUint32); s != "" {
+			tt.Errorf("lookup unknown = %s", s)
+		}
+		for id := uint32(0); id < 2; id += 1 {
+			if s := in.Lookup(id); s != []string{"a", "b"}[id] {
+				tt.Errorf("lookup %d = %s", id, s)
+			}
+		}
+	})
+
+	t.Run("exceed_buffer", func(tt *testing.T) {
+		a := NewArena(4 * 1024)
+		defer a.Release()
+		in := NewInterner(a)
+
+		ids := make([]uint32, 2000)
+		for i := 0; i < 2000; i += 1 {
+			ids[i] = in.ID("hostname-" + strconv.Itoa(i))
+		}
+		for i := 0; i < 2000; i += 1 {
+			expect := "hostname-" + strconv.Itoa(i)
+			if s := in.Lookup(ids[i]); s != expect {
+				tt.Errorf("lookup = %s, expect %s", s, expect)
+			}
+			if s := in.Intern(expect); s != expect {
+				tt.Errorf("intern = %s, expect %s", s, expect)
+			}
+		}
+		if in.Len() != 2000 {
+			tt.Errorf("len = %d, expect 2000", in.Len())
+		}
+	})
+
+	t.Run("WithFixedCapacity", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		in := NewInterner(a, WithFixedCapacity(8))
+
+		i := 0
+		for ; ; i += 1 {
+			if _, err := in.TryID(strconv.Itoa(i)); err != nil {
+				if errors.Is(err, ErrFull) != true {
+					tt.Errorf("unexpected error: %+v", err)
+				}
+				break
+			}
+		}
+		if s := in.Intern("new"); s != "new" {
+			tt.Errorf("intern = %s, expect new", s)
+		}
+		if id := in.ID("new"); id != math.MaxUint32 {
+			tt.Errorf("id = %d, expect MaxUint32", id)
+		}
+		if id := in.ID("0"); id != 0 {
+			tt.Errorf("id = %d, expect 0", id)
+		}
+		if in.Len() != i {
+			tt.Errorf("len = %d, expect %d", in.Len(), i)
+		}
+	})
+}
@@ -0,0 +1,567 @@
+package armap
+
+import (
+	"encoding/binary"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+type containerKind uint8
+
+const (
+	kindArray  containerKind = 0
+	kindBitmap containerKind = 1
+	kindRun    containerKind = 2
+
+	maxArrayCard = 4096
+	bitmapWords  = 1024
+)
+
+// container holds the low 16 bits of members sharing the same high 16 bits
+type container struct {
+	kind   containerKind
+	card   int
+	array  []uint16 // sorted values (kindArray)
+	bitmap []uint64 // 1024 words (kindBitmap)
+	runs   []uint16 // [start, last] pairs (kindRun)
+}
+
+// IntSet is compressed set of uint32 (roaring bitmap).
+// members are split into containers of arrays, bitmaps or runs allocated in the arena.
+// containers larger than the arena buffer are allocated on the heap.
+type IntSet struct {
+	arena      Arena
+	keys       []uint16
+	containers []container
+	count      int
+}
+
+func (s *IntSet) Len() int {
+	return s.count
+}
+
+func (s *IntSet) find(hi uint16) (int, bool) {
+	return slices.BinarySearch(s.keys, hi)
+}
+
+func (s *IntSet) Add(x uint32) bool {
+	hi, lo := uint16(x>>16), uint16(x)
+	i, ok := s.find(hi)
+	if ok != true {
+		s.keys = slices.Insert(s.keys, i, hi)
+		s.containers = slices.Insert(s.containers, i, container{kind: kindArray})
+	}
+	if s.add(&s.containers[i], lo) != true {
+		return false
+	}
+	s.count += 1
+	return true
+}
+
+func (s *IntSet) Contains(x uint32) bool {
+	i, ok := s.find(uint16(x >> 16))
+	if ok != true {
+		return false
+	}
+	return s.containers[i].contains(uint16(x))
+}
+
+func (s *IntSet) Remove(x uint32) bool {
+	i, ok := s.find(uint16(x >> 16))
+	if ok != true {
+		return false
+	}
+	c := &s.containers[i]
+	if s.remove(c, uint16(x)) != true {
+		return false
+	}
+	s.count -= 1
+	if c.card == 0 {
+		s.keys = slices.Delete(s.keys, i, i+1)
+		s.containers = slices.Delete(s.containers, i, i+1)
+	}
+	return true
+}
+
+// Rank returns the number of members less than or equal to x
+func (s *IntSet) Rank(x uint32) int {
+	hi, lo := uint16(x>>16), uint16(x)
+	rank := 0
+	for i, key := range s.keys {
+		if hi < key {
+			break
+		}
+		if key < hi {
+			rank += s.containers[i].card
+			continue
+		}
+		rank += s.containers[i].rank(lo)
+	}
+	return rank
+}
+
+// Select returns the i-th smallest member (0-based)
+func (s *IntSet) Select(i int) (uint32, bool) {
+	if i < 0 || s.count <= i {
+		return 0, false
+	}
+	for ci := range s.containers {
+		c := &s.containers[ci]
+		if i < c.card {
+			return uint32(s.keys[ci])<<16 | uint32(c.selectAt(i)), true
+		}
+		i -= c.card
+	}
+	return 0, false
+}
+
+// All iterates members in ascending order
+func (s *IntSet) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for ci := range s.containers {
+			hi := uint32(s.keys[ci]) << 16
+			for lo := range s.containers[ci].all() {
+				if yield(hi|uint32(lo)) != true {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *IntSet) Union(other *IntSet) {
+	s.combine(other, true, func(a, b uint64) uint64 { return a | b })
+}
+
+func (s *IntSet) Intersect(other *IntSet) {
+	s.combine(other, false, func(a, b uint64) uint64 { return a & b })
+}
+
+func (s *IntSet) Difference(other *IntSet) {
+	s.combine(other, false, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// combine applies op word by word on containers of the same key.
+// keys only in other are added when includeOther is true, keys only in s are combined with empty container.
+func (s *IntSet) combine(other *IntSet, includeOther bool, op func(a, b uint64) uint64) {
+	keys := make([]uint16, 0, len(s.keys)+len(other.keys))
+	containers := make([]container, 0, len(s.keys)+len(other.keys))
+	var wa, wb [bitmapWords]uint64
+
+	i, j := 0, 0
+	for i < len(s.keys) || j < len(other.keys) {
+		var key uint16
+		clear(wa[:])
+		clear(wb[:])
+		switch {
+		case j == len(other.keys) || (i < len(s.keys) && s.keys[i] < other.keys[j]):
+			key = s.keys[i]
+			s.containers[i].fillWords(&wa)
+			i += 1
+		case i == len(s.keys) || other.keys[j] < s.keys[i]:
+			key = other.keys[j]
+			if includeOther != true {
+				j += 1
+				continue
+			}
+			other.containers[j].fillWords(&wb)
+			j += 1
+		default:
+			key = s.keys[i]
+			s.containers[i].fillWords(&wa)
+			other.containers[j].fillWords(&wb)
+			i += 1
+			j += 1
+		}
+
+		for w := range wa {
+			wa[w] = op(wa[w], wb[w])
+		}
+		if c, ok := s.fromWords(&wa); ok {
+			keys = append(keys, key)
+			containers = append(containers, c)
+		}
+	}
+
+	s.keys = keys
+	s.containers = containers
+	s.count = 0
+	for _, c := range s.containers {
+		s.count += c.card
+	}
+}
+
+// RunOptimize converts containers to run encoding where it is smaller
+func (s *IntSet) RunOptimize() {
+	for i := range s.containers {
+		c := &s.containers[i]
+		if c.kind == kindRun {
+			continue
+		}
+		var words [bitmapWords]uint64
+		c.fillWords(&words)
+		runs := wordsToRuns(&words)
+		size := c.card * 2
+		if c.kind == kindBitmap {
+			size = bitmapWords * 8
+		}
+		if len(runs)*2 < size {
+			c.kind = kindRun
+			c.runs = makeFit[uint16](s.arena, len(runs), alignedCap16(len(runs)))
+			copy(c.runs, runs)
+			c.array = nil
+			c.bitmap = nil
+		}
+	}
+}
+
+func (s *IntSet) Clear() {
+	s.keys = nil
+	s.containers = nil
+	s.count = 0
+}
+
+// MarshalBinary encodes containers as: count, then per container key, kind, length and values
+func (s *IntSet) MarshalBinary() ([]byte, error) {
+	data := binary.LittleEndian.AppendUint32(nil, uint32(len(s.keys)))
+	for i, key := range s.keys {
+		c := &s.containers[i]
+		data = binary.LittleEndian.AppendUint16(data, key)
+		data = append(data, byte(c.kind))
+		switch c.kind {
+		case kindArray:
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(c.array)))
+			for _, v := range c.array {
+				data = binary.LittleEndian.AppendUint16(data, v)
+			}
+		case kindBitmap:
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(c.bitmap)))
+			for _, w := range c.bitmap {
+				data = binary.LittleEndian.AppendUint64(data, w)
+			}
+		case kindRun:
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(c.runs)))
+			for _, v := range c.runs {
+				data = binary.LittleEndian.AppendUint16(data, v)
+			}
+		}
+	}
+	return data, nil
+}
+
+func (s *IntSet) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return ErrInvalidData
+	}
+	n := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+
+	s.Clear()
+	hasRun := false
+	for i := 0; i < n; i += 1 {
+		if len(data) < 7 {
+			return ErrInvalidData
+		}
+		key := binary.LittleEndian.Uint16(data)
+		kind := containerKind(data[2])
+		size := int(binary.LittleEndian.Uint32(data[3:]))
+		data = data[7:]
+
+		var words [bitmapWords]uint64
+		switch kind {
+		case kindArray, kindRun:
+			if len(data) < size*2 || (kind == kindRun && size%2 != 0) {
+				return ErrInvalidData
+			}
+			values := make([]uint16, size)
+			for j := range values {
+				values[j] = binary.LittleEndian.Uint16(data[j*2:])
+			}
+			data = data[size*2:]
+			c := container{kind: kind}
+			if kind == kindRun {
+				c.runs = values
+				hasRun = true
+			} else {
+				c.array = values
+			}
+			c.fillWords(&words)
+		case kindBitmap:
+			if size != bitmapWords || len(data) < size*8 {
+				return ErrInvalidData
+			}
+			for j := range words {
+				words[j] = binary.LittleEndian.Uint64(data[j*8:])
+			}
+			data = data[size*8:]
+		default:
+			return ErrInvalidData
+		}
+		if i != 0 && key <= s.keys[len(s.keys)-1] {
+			return ErrInvalidData
+		}
+		c, ok := s.fromWords(&words)
+		if ok != true {
+			continue
+		}
+		s.keys = append(s.keys, key)
+		s.containers = append(s.containers, c)
+		s.count += c.card
+	}
+	if 0 < len(data) {
+		return ErrInvalidData
+	}
+	if hasRun {
+		s.RunOptimize()
+	}
+	return nil
+}
+
+func (s *IntSet) add(c *container, lo uint16) bool {
+	if c.kind == kindRun {
+		s.unrun(c)
+	}
+	switch c.kind {
+	case kindArray:
+		i, found := slices.BinarySearch(c.array, lo)
+		if found {
+			return false
+		}
+		if c.card == maxArrayCard {
+			s.toBitmap(c)
+			return s.add(c, lo)
+		}
+		if len(c.array) == cap(c.array) {
+			array := makeFit[uint16](s.arena, len(c.array), alignedCap16(max(cap(c.array)*2, 4)))
+			copy(array, c.array)
+			c.array = array
+		}
+		c.array = c.array[:len(c.array)+1]
+		copy(c.array[i+1:], c.array[i:])
+		c.array[i] = lo
+	case kindBitmap:
+		mask := uint64(1) << (lo & 63)
+		if c.bitmap[lo>>6]&mask != 0 {
+			return false
+		}
+		c.bitmap[lo>>6] |= mask
+	}
+	c.card += 1
+	return true
+}
+
+func (s *IntSet) remove(c *container, lo uint16) bool {
+	if c.contains(lo) != true {
+		return false
+	}
+	if c.kind == kindRun {
+		s.unrun(c)
+	}
+	switch c.kind {
+	case kindArray:
+		i, _ := slices.BinarySearch(c.array, lo)
+		c.array = slices.Delete(c.array, i, i+1)
+	case kindBitmap:
+		c.bitmap[lo>>6] &^= uint64(1) << (lo & 63)
+	}
+	c.card -= 1
+	// convert back well below maxArrayCard, so that add/remove around the limit does not reallocate every time
+	if c.kind == kindBitmap && c.card <= maxArrayCard/2 {
+		var words [bitmapWords]uint64
+		copy(words[:], c.bitmap)
+		*c, _ = s.fromWords(&words)
+	}
+	return true
+}
+
+// unrun converts run container to array or bitmap before mutation
+func (s *IntSet) unrun(c *container) {
+	var words [bitmapWords]uint64
+	c.fillWords(&words)
+	*c, _ = s.fromWords(&words)
+}
+
+func (s *IntSet) toBitmap(c *container) {
+	var words [bitmapWords]uint64
+	c.fillWords(&words)
+	bitmap := makeFit[uint64](s.arena, bitmapWords, bitmapWords)
+	copy(bitmap, words[:])
+	*c = container{kind: kindBitmap, card: c.card, bitmap: bitmap}
+}
+
+// fromWords builds array or bitmap container from words, reports false if empty
+func (s *IntSet) fromWords(words *[bitmapWords]uint64) (container, bool) {
+	card := 0
+	for _, w := range words {
+		card += bits.OnesCount64(w)
+	}
+	if card == 0 {
+		return container{}, false
+	}
+	if maxArrayCard < card {
+		bitmap := makeFit[uint64](s.arena, bitmapWords, bitmapWords)
+		copy(bitmap, words[:])
+		return container{kind: kindBitmap, card: card, bitmap: bitmap}, true
+	}
+	array := makeFit[uint16](s.arena, 0, alignedCap16(card))
+	for wi, w := range words {
+		for w != 0 {
+			array = append(array, uint16(wi<<6+bits.TrailingZeros64(w)))
+			w &= w - 1
+		}
+	}
+	return container{kind: kindArray, card: card, array: array}, true
+}
+
+func (c *container) contains(lo uint16) bool {
+	switch c.kind {
+	case kindArray:
+		_, found := slices.BinarySearch(c.array, lo)
+		return found
+	case kindBitmap:
+		return c.bitmap[lo>>6]&(uint64(1)<<(lo&63)) != 0
+	case kindRun:
+		for i := 0; i < len(c.runs); i += 2 {
+			if lo < c.runs[i] {
+				return false
+			}
+			if lo <= c.runs[i+1] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *container) rank(lo uint16) int {
+	switch c.kind {
+	case kindArray:
+		i, found := slices.BinarySearch(c.array, lo)
+		if found {
+			return i + 1
+		}
+		return i
+	case kindBitmap:
+		rank := 0
+		for _, w := range c.bitmap[:lo>>6] {
+			rank += bits.OnesCount64(w)
+		}
+		return rank + bits.OnesCount64(c.bitmap[lo>>6]&(^uint64(0)>>(63-(lo&63))))
+	case kindRun:
+		rank := 0
+		for i := 0; i < len(c.runs); i += 2 {
+			if lo < c.runs[i] {
+				break
+			}
+			rank += int(min(lo, c.runs[i+1])-c.runs[i]) + 1
+		}
+		return rank
+	}
+	return 0
+}
+
+func (c *container) selectAt(i int) uint16 {
+	switch c.kind {
+	case kindArray:
+		return c.array[i]
+	case kindBitmap:
+		for wi, w := range c.bitmap {
+			n := bits.OnesCount64(w)
+			if i < n {
+				return uint16(wi<<6) + uint16(selectInWord(w, i))
+			}
+			i -= n
+		}
+	case kindRun:
+		for r := 0; r < len(c.runs); r += 2 {
+			n := int(c.runs[r+1]-c.runs[r]) + 1
+			if i < n {
+				return c.runs[r] + uint16(i)
+			}
+			i -= n
+		}
+	}
+	return 0
+}
+
+func (c *container) all() iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		switch c.kind {
+		case kindArray:
+			for _, v := range c.array {
+				if yield(v) != true {
+					return
+				}
+			}
+		case kindBitmap:
+			for wi, w := range c.bitmap {
+				for w != 0 {
+					if yield(uint16(wi<<6+bits.TrailingZeros64(w))) != true {
+						return
+					}
+					w &= w - 1
+				}
+			}
+		case kindRun:
+			for r := 0; r < len(c.runs); r += 2 {
+				for v := int(c.runs[r]); v <= int(c.runs[r+1]); v += 1 {
+					if yield(uint16(v)) != true {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func (c *container) fillWords(words *[bitmapWords]uint64) {
+	switch c.kind {
+	case kindArray:
+		for _, v := range c.array {
+			words[v>>6] |= uint64(1) << (v & 63)
+		}
+	case kindBitmap:
+		copy(words[:], c.bitmap)
+	case kindRun:
+		for r := 0; r+1 < len(c.runs); r += 2 {
+			for v := int(c.runs[r]); v <= int(c.runs[r+1]); v += 1 {
+				words[v>>6] |= uint64(1) << (v & 63)
+			}
+		}
+	}
+}
+
+func wordsToRuns(words *[bitmapWords]uint64) []uint16 {
+	runs := []uint16{}
+	inRun := false
+	for v := 0; v < bitmapWords*64; v += 1 {
+		set := words[v>>6]&(uint64(1)<<(v&63)) != 0
+		if set && inRun != true {
+			runs = append(runs, uint16(v), 0)
+			inRun = true
+		}
+		if set != true && inRun {
+			runs[len(runs)-1] = uint16(v - 1)
+			inRun = false
+		}
+	}
+	if inRun {
+		runs[len(runs)-1] = uint16(bitmapWords*64 - 1)
+	}
+	return runs
+}
+
+// alignedCap16 rounds up uint16 capacity so that arena allocations stay 8 bytes aligned
+func alignedCap16(n int) int {
+	return (n + 3) &^ 3
+}
+
+func NewIntSet(arena Arena) *IntSet {
+	return &IntSet{
+		arena:      arena,
+		keys:       nil,
+		containers: nil,
+		count:      0,
+	}
+}
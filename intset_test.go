@@ -0,0 +1,217 @@
+package armap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func TestIntSet(t *testing.T) {
+	t.Run("random", func(tt *testing.T) {
+		a := NewArena(4 * 1024 * 1024)
+		defer a.Release()
+		s := NewIntSet(a)
+
+		expect := make(map[uint32]struct{})
+		r := rand.New(rand.NewPCG(1, 2))
+		for i := 0; i < 50_000; i += 1 {
+			// sparse keys, and one dense container to exercise bitmaps
+			x := r.Uint32N(1 << 20)
+			if i%2 == 0 {
+				x = 3<<16 | r.Uint32N(1<<13)
+			}
+			if r.IntN(4) == 0 {
+				_, exists := expect[x]
+				if s.Remove(x) != exists {
+					tt.Fatalf("remove %d mismatch", x)
+				}
+				delete(expect, x)
+				continue
+			}
+			_, exists := expect[x]
+			if s.Add(x) == exists {
+				tt.Fatalf("add %d mismatch", x)
+			}
+			expect[x] = struct{}{}
+		}
+
+		if s.Len() != len(expect) {
+			tt.Errorf("len = %d, expect %d", s.Len(), len(expect))
+		}
+		sorted := make([]uint32, 0, len(expect))
+		for x := range expect {
+			sorted = append(sorted, x)
+		}
+		slices.Sort(sorted)
+		if slices.Equal(slices.Collect(s.All()), sorted) != true {
+			tt.Errorf("all mismatch")
+		}
+		for _, i := range []int{0, 1, len(sorted) / 2, len(sorted) - 1} {
+			if x, ok := s.Select(i); ok != true || x != sorted[i] {
+				tt.Errorf("select(%d) = %d, expect %d", i, x, sorted[i])
+			}
+			if rank := s.Rank(sorted[i]); rank != i+1 {
+				tt.Errorf("rank(%d) = %d, expect %d", sorted[i], rank, i+1)
+			}
+		}
+	})
+
+	t.Run("exceed_buffer", func(tt *testing.T) {
+		// containers take much more than one arena buffer, bitmaps are larger than the buffer
+		a := NewArena(4 * 1024)
+		defer a.Release()
+		s := NewIntSet(a)
+
+		expect := make([]uint32, 0, 20_000)
+		for i := uint32(0); i < 20_000; i += 1 {
+			x := i * 97
+			if i%4 == 0 {
+				x = 7<<16 | i
+			}
+			s.Add(x)
+			expect = append(expect, x)
+		}
+		slices.Sort(expect)
+		if s.Len() != len(expect) {
+			tt.Errorf("len = %d, expect %d", s.Len(), len(expect))
+		}
+		if slices.Equal(slices.Collect(s.All()), expect) != true {
+			tt.Errorf("all mismatch")
+		}
+		s.RunOptimize()
+		for _, x := range expect {
+			if s.Contains(x) != true {
+				tt.Errorf("%d not found", x)
+			}
+		}
+	})
+
+	t.Run("bitmap_hysteresis", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s := NewIntSet(a)
+
+		for i := uint32(0); i < 4096; i += 2 {
+			s.Add(i)
+		}
+		for i := uint32(1); i < 4096; i += 2 {
+			s.Add(i)
+		}
+		for i := 0; i < 2000; i += 1 {
+			s.Add(5000)
+			if s.containers[0].kind != kindBitmap {
+				tt.Fatalf("container must be bitmap")
+			}
+			bitmap := unsafe.SliceData(s.containers[0].bitmap)
+			s.Remove(5000)
+			if s.containers[0].kind != kindBitmap || unsafe.SliceData(s.containers[0].bitmap) != bitmap {
+				tt.Fatalf("bitmap must be kept around the limit")
+			}
+		}
+		if s.Len() != 4096 {
+			tt.Errorf("len = %d, expect 4096", s.Len())
+		}
+
+		for i := uint32(0); i < 4096-maxArrayCard/2; i += 1 {
+			s.Remove(i)
+		}
+		if s.containers[0].kind != kindArray {
+			tt.Errorf("container must be array")
+		}
+		if all := slices.Collect(s.All()); len(all) != maxArrayCard/2 || all[0] != 4096-maxArrayCard/2 {
+			tt.Errorf("all len = %d", len(all))
+		}
+	})
+
+	t.Run("RunOptimize", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s := NewIntSet(a)
+		for x := uint32(100); x < 10_000; x += 1 {
+			s.Add(x)
+		}
+		s.RunOptimize()
+		if s.containers[0].kind != kindRun {
+			tt.Errorf("expect run container")
+		}
+		if s.Contains(99) || s.Contains(100) != true || s.Contains(9_999) != true || s.Contains(10_000) {
+			tt.Errorf("contains mismatch")
+		}
+		if r := s.Rank(199); r != 100 {
+			tt.Errorf("rank = %d, expect 100", r)
+		}
+		if x, _ := s.Select(100); x != 200 {
+			tt.Errorf("select = %d, expect 200", x)
+		}
+		if s.Remove(5000) != true || s.Contains(5000) {
+			tt.Errorf("remove 5000")
+		}
+		if s.Len() != 9_899 {
+			tt.Errorf("len = %d, expect 9899", s.Len())
+		}
+	})
+
+	t.Run("algebra", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s1 := NewIntSet(a)
+		s2 := NewIntSet(a)
+		for x := uint32(0); x < 200_000; x += 2 {
+			s1.Add(x)
+		}
+		for x := uint32(0); x < 300_000; x += 3 {
+			s2.Add(x)
+		}
+
+		s1.Intersect(s2)
+		if s1.Len() != 33_334 {
+			tt.Errorf("intersect len = %d, expect 33334", s1.Len())
+		}
+		for x := range s1.All() {
+			if x%6 != 0 {
+				tt.Fatalf("%d is not multiple of 6", x)
+			}
+		}
+		s1.Union(s2)
+		if s1.Len() != s2.Len() {
+			tt.Errorf("union len = %d, expect %d", s1.Len(), s2.Len())
+		}
+		s1.Difference(s2)
+		if s1.Len() != 0 {
+			tt.Errorf("difference len = %d, expect 0", s1.Len())
+		}
+	})
+
+	t.Run("MarshalBinary", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s1 := NewIntSet(a)
+		for x := uint32(0); x < 10_000; x += 1 {
+			s1.Add(x)
+		}
+		for x := uint32(1 << 20); x < 1<<20+100_000; x += 9 {
+			s1.Add(x)
+		}
+		s1.Add(1 << 30)
+		s1.RunOptimize()
+
+		data, err := s1.MarshalBinary()
+		if err != nil {
+			tt.Fatalf("unexpected error: %+v", err)
+		}
+		s2 := NewIntSet(a)
+		if err := s2.UnmarshalBinary(data); err != nil {
+			tt.Fatalf("unexpected error: %+v", err)
+		}
+		if s2.Len() != s1.Len() {
+			tt.Errorf("len = %d, expect %d", s2.Len(), s1.Len())
+		}
+		if slices.Equal(slices.Collect(s1.All()), slices.Collect(s2.All())) != true {
+			tt.Errorf("mismatch after unmarshal")
+		}
+		if err := s2.UnmarshalBinary(data[:len(data)-1]); err == nil {
+			tt.Errorf("expect error for truncated data")
+		}
+	})
+}
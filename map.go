@@ -1,17 +1,25 @@
 package armap
 
 import (
+	"errors"
 	"fmt"
+	"iter"
+	"math/rand/v2"
 	"unsafe"
 
 	"github.com/dolthub/maphash"
 )
 
+var (
+	ErrFull = errors.New("armap: map is full")
+)
+
 type bucketState byte
 
 const (
 	stateEmpty bucketState = 0
 	stateUsed  bucketState = 1
+	stateMoved bucketState = 2 // tombstone in old table while incremental resizing
 )
 
 type bucket[K comparable, V any] struct {
@@ -28,6 +36,19 @@ type Map[K comparable, V any] struct {
 	count      int
 	capacity   int
 	loadFactor float64
+
+	minCapacity   int
+	minLoadFactor float64
+	fixed         bool
+	valuePointers bool
+
+	// incremental resize
+	oldBuckets  []byte
+	oldCapacity int
+	migrateIdx  int
+	migrateStep int
+
+	popIdx int
 }
 
 func (m *Map[K, V]) getBucket(idx int) *bucket[K, V] {
@@ -35,24 +56,71 @@ func (m *Map[K, V]) getBucket(idx int) *bucket[K, V] {
 	return (*bucket[K, V])(unsafe.Pointer(&m.buckets[offset]))
 }
 
+func (m *Map[K, V]) getOldBucket(idx int) *bucket[K, V] {
+	offset := uintptr(idx) * m.bucketSize
+	return (*bucket[K, V])(unsafe.Pointer(&m.oldBuckets[offset]))
+}
+
 func (m *Map[K, V]) Len() int {
 	return m.count
 }
 
 func (m *Map[K, V]) index(key K) int {
-	return int(m.hasher.Hash(key)) & (m.capacity - 1)
+	return m.indexHash(m.hasher.Hash(key))
 }
 
+func (m *Map[K, V]) indexHash(hash uint64) int {
+	return int(hash) & (m.capacity - 1)
+}
+
+// Hash returns the hash of key used by *Hashed methods.
+// hashes are interchangeable between maps sharing a hasher (see WithHasher).
+func (m *Map[K, V]) Hash(key K) uint64 {
+	return m.hasher.Hash(key)
+}
+
+func (m *Map[K, V]) Hasher() maphash.Hasher[K] {
+	return m.hasher
+}
+
+// Set inserts or updates key.
+// With WithFixedCapacity, a new key is not inserted once the table reaches its load limit
+// and Set returns as if the key did not exist; use TrySet to detect ErrFull.
 func (m *Map[K, V]) Set(key K, value V) (old V, found bool) {
+	return m.SetHashed(m.hasher.Hash(key), key, value)
+}
+
+// SetHashed is Set with hash precomputed by Hash(key)
+func (m *Map[K, V]) SetHashed(hash uint64, key K, value V) (old V, found bool) {
+	if m.fixed {
+		old, found, _ := m.trySet(hash, key, value)
+		return old, found
+	}
+	m.migrate(m.migrateStep)
+
 	if m.loadFactor < (float64(m.count) / float64(m.capacity)) {
-		m.resize(m.capacity * 2)
+		m.startResize(m.capacity * 2)
 	}
 
-	idx := m.index(key)
-	startIdx := idx
+	return m.set(hash, key, value, NewTypeArena[K](m.arena), NewTypeArena[V](m.arena))
+}
 
-	ka := NewTypeArena[K](m.arena)
-	va := NewTypeArena[V](m.arena)
+// set inserts or updates key without checking the load factor
+func (m *Map[K, V]) set(hash uint64, key K, value V, ka TypeArena[K], va TypeArena[V]) (old V, found bool) {
+	if m.migrating() {
+		if oldIdx, ok := m.findOld(hash, key); ok {
+			// move to new table with updated value
+			b := m.getOldBucket(oldIdx)
+			old = b.value
+			found = true
+			m.insertRaw(b.key, va.Clone(value))
+			m.markMoved(b)
+			return
+		}
+	}
+
+	idx := m.indexHash(hash)
+	startIdx := idx
 	for {
 		b := m.getBucket(idx)
 		if b.state == stateEmpty {
@@ -74,30 +142,247 @@ func (m *Map[K, V]) Set(key K, value V) (old V, found bool) {
 		idx = (idx + 1) & (m.capacity - 1)
 		if idx == startIdx {
 			m.resize(m.capacity * 2)
-			idx = m.index(key)
+			idx = m.indexHash(hash)
+			startIdx = idx
+		}
+	}
+}
+
+// TrySet is Set that never resizes a table created by WithFixedCapacity.
+// it returns ErrFull when key is new and the table has reached its load limit.
+func (m *Map[K, V]) TrySet(key K, value V) (old V, found bool, err error) {
+	if m.fixed != true {
+		old, found = m.Set(key, value)
+		return old, found, nil
+	}
+	return m.trySet(m.hasher.Hash(key), key, value)
+}
+
+func (m *Map[K, V]) trySet(hash uint64, key K, value V) (old V, found bool, err error) {
+	if m.loadFactor < (float64(m.count+1) / float64(m.capacity)) {
+		if b := m.find(hash, key); b != nil {
+			old = b.value
+			b.value = NewTypeArena[V](m.arena).Clone(value)
+			return old, true, nil
+		}
+		return old, false, ErrFull
+	}
+	old, found = m.set(hash, key, value, NewTypeArena[K](m.arena), NewTypeArena[V](m.arena))
+	return old, found, nil
+}
+
+// full reports whether a new key would be rejected with ErrFull
+func (m *Map[K, V]) full() bool {
+	return m.fixed && m.loadFactor < (float64(m.count+1)/float64(m.capacity))
+}
+
+// entry returns the bucket of key, inserting key with zero value if it does not exist
+func (m *Map[K, V]) entry(hash uint64, key K) (b *bucket[K, V], found bool, err error) {
+	if b := m.find(hash, key); b != nil {
+		return b, true, nil
+	}
+	if m.fixed {
+		if m.full() {
+			return nil, false, ErrFull
+		}
+	} else {
+		m.migrate(m.migrateStep)
+		if m.loadFactor < (float64(m.count) / float64(m.capacity)) {
+			m.startResize(m.capacity * 2)
+		}
+	}
+
+	ka := NewTypeArena[K](m.arena)
+	idx := m.indexHash(hash)
+	startIdx := idx
+	for {
+		b := m.getBucket(idx)
+		if b.state == stateEmpty {
+			b.key = ka.Clone(key)
+			b.state = stateUsed
+			m.count += 1
+			return b, false, nil
+		}
+		idx = (idx + 1) & (m.capacity - 1)
+		if idx == startIdx {
+			m.resize(m.capacity * 2)
+			idx = m.indexHash(hash)
 			startIdx = idx
 		}
 	}
 }
 
+// Grow ensures room for n more entries under the load factor with a single rehash
+func (m *Map[K, V]) Grow(n int) {
+	if m.fixed {
+		return
+	}
+	capacity := m.fitCapacity(m.count + n)
+	if m.capacity < capacity {
+		m.resize(capacity)
+	}
+}
+
+// bulkLoadBatch is the number of entries BulkLoad buffers before inserting them
+const bulkLoadBatch = 1024
+
+// BulkLoad inserts all entries of seq in batches, sizing the table once per batch.
+// seq is iterated only once and at most bulkLoadBatch entries are buffered on the heap,
+// call Grow beforehand when the number of entries is known to size the table only once.
+func (m *Map[K, V]) BulkLoad(seq iter.Seq2[K, V]) {
+	keys := make([]K, 0, bulkLoadBatch)
+	values := make([]V, 0, bulkLoadBatch)
+	for k, v := range seq {
+		keys = append(keys, k)
+		values = append(values, v)
+		if len(keys) == bulkLoadBatch {
+			m.SetMany(keys, values)
+			keys, values = keys[:0], values[:0]
+		}
+	}
+	m.SetMany(keys, values)
+}
+
+// setBatch is set for batch operations sized by Grow beforehand, a full fixed table skips new keys like Set
+func (m *Map[K, V]) setBatch(hash uint64, key K, value V, ka TypeArena[K], va TypeArena[V]) {
+	if m.fixed {
+		m.trySet(hash, key, value)
+		return
+	}
+	m.set(hash, key, value, ka, va)
+}
+
+func (m *Map[K, V]) hashMany(keys []K) []uint64 {
+	hashes := make([]uint64, len(keys))
+	for i, key := range keys {
+		hashes[i] = m.hasher.Hash(key)
+	}
+	return hashes
+}
+
+// reserve sizes the table for n more entries with a single resize,
+// with WithIncrementalResize the rehash is spread over the following operations
+func (m *Map[K, V]) reserve(n int) {
+	if m.fixed {
+		return
+	}
+	capacity := m.fitCapacity(m.count + n)
+	if m.capacity < capacity {
+		m.startResize(capacity)
+	}
+}
+
+// SetMany sets keys[i] to values[i], sizing the table once and reusing one typed arena for the batch
+func (m *Map[K, V]) SetMany(keys []K, values []V) {
+	if len(keys) != len(values) {
+		panic(fmt.Sprintf("armap: keys and values length mismatch: %d != %d", len(keys), len(values)))
+	}
+	m.reserve(len(keys))
+
+	hashes := m.hashMany(keys)
+	ka := NewTypeArena[K](m.arena)
+	va := NewTypeArena[V](m.arena)
+	for i, key := range keys {
+		m.migrate(m.migrateStep)
+		m.setBatch(hashes[i], key, values[i], ka, va)
+	}
+}
+
+// GetMany stores the value of keys[i] into values[i] and reports whether each key exists
+func (m *Map[K, V]) GetMany(keys []K, values []V) []bool {
+	if len(values) < len(keys) {
+		panic(fmt.Sprintf("armap: values is shorter than keys: %d < %d", len(values), len(keys)))
+	}
+	hashes := m.hashMany(keys)
+
+	found := make([]bool, len(keys))
+	for i, key := range keys {
+		if b := m.find(hashes[i], key); b != nil {
+			values[i] = b.value
+			found[i] = true
+		}
+	}
+	return found
+}
+
+// DeleteMany deletes keys and returns the number of deleted keys
+func (m *Map[K, V]) DeleteMany(keys []K) int {
+	hashes := m.hashMany(keys)
+
+	deleted := 0
+	for i, key := range keys {
+		if _, ok := m.DeleteHashed(hashes[i], key); ok {
+			deleted += 1
+		}
+	}
+	return deleted
+}
+
 func (m *Map[K, V]) Get(key K) (val V, found bool) {
+	return m.GetHashed(m.hasher.Hash(key), key)
+}
+
+// GetHashed is Get with hash precomputed by Hash(key)
+func (m *Map[K, V]) GetHashed(hash uint64, key K) (val V, found bool) {
+	if b := m.find(hash, key); b != nil {
+		return b.value, true
+	}
+	return
+}
+
+// GetPtr returns a pointer to the value stored in the bucket, or nil if key does not exist.
+// the pointer is valid until the next mutating call (Set, Delete, Clear, resize).
+// buckets are not scanned by GC, so only pointer-free data (numbers, bools, arrays and structs of them) may be
+// written through it; assigning strings, slices, maps or pointers leaves them unreachable for GC. use Modify instead.
+func (m *Map[K, V]) GetPtr(key K) *V {
+	if b := m.find(m.hasher.Hash(key), key); b != nil {
+		return &b.value
+	}
+	return nil
+}
+
+// Modify edits the value of key in place and reports whether key exists.
+// when V contains pointers, the edited value is cloned into the arena after fn returns,
+// so that nothing in the bucket references memory GC cannot see.
+func (m *Map[K, V]) Modify(key K, fn func(*V)) bool {
+	if b := m.find(m.hasher.Hash(key), key); b != nil {
+		if m.valuePointers != true {
+			fn(&b.value)
+			return true
+		}
+		// edit a GC visible copy, the bucket only receives the arena clone
+		v := b.value
+		fn(&v)
+		b.value = NewTypeArena[V](m.arena).Clone(v)
+		return true
+	}
+	return false
+}
+
+// find returns the bucket holding key in either table, or nil
+func (m *Map[K, V]) find(hash uint64, key K) *bucket[K, V] {
 	if m.capacity == 0 {
-		return
+		return nil
 	}
-	idx := m.index(key)
+	if m.migrating() {
+		if oldIdx, ok := m.findOld(hash, key); ok {
+			return m.getOldBucket(oldIdx)
+		}
+	}
+	idx := m.indexHash(hash)
 	startIdx := idx
 
 	for {
 		b := m.getBucket(idx)
 		if b.state == stateEmpty {
-			return
+			return nil
 		}
 		if b.state == stateUsed && b.key == key {
-			return b.value, true
+			return b
 		}
 		idx = (idx + 1) & (m.capacity - 1)
 		if idx == startIdx {
-			return
+			return nil
 		}
 	}
 }
@@ -106,6 +391,14 @@ func (m *Map[K, V]) Scan(iter func(K, V) bool) {
 	if m.capacity == 0 {
 		return
 	}
+	for i := 0; i < m.oldCapacity; i += 1 {
+		b := m.getOldBucket(i)
+		if b.state == stateUsed {
+			if iter(b.key, b.value) != true {
+				return
+			}
+		}
+	}
 	for i := 0; i < m.capacity; i += 1 {
 		b := m.getBucket(i)
 		if b.state == stateUsed {
@@ -117,10 +410,28 @@ func (m *Map[K, V]) Scan(iter func(K, V) bool) {
 }
 
 func (m *Map[K, V]) Delete(key K) (old V, found bool) {
+	return m.DeleteHashed(m.hasher.Hash(key), key)
+}
+
+// DeleteHashed is Delete with hash precomputed by Hash(key)
+func (m *Map[K, V]) DeleteHashed(hash uint64, key K) (old V, found bool) {
 	if m.capacity == 0 {
 		return
 	}
-	idx := m.index(key)
+	m.migrate(m.migrateStep)
+
+	if m.migrating() {
+		if oldIdx, ok := m.findOld(hash, key); ok {
+			b := m.getOldBucket(oldIdx)
+			old = b.value
+			found = true
+			m.count -= 1
+			m.markMoved(b)
+			m.shrinkIfSparse()
+			return
+		}
+	}
+	idx := m.indexHash(hash)
 	startIdx := idx
 
 	for {
@@ -133,6 +444,7 @@ func (m *Map[K, V]) Delete(key K) (old V, found bool) {
 			found = true
 			m.count -= 1
 			m.shiftBack(idx)
+			m.shrinkIfSparse()
 			return
 		}
 		idx = (idx + 1) & (m.capacity - 1)
@@ -142,6 +454,223 @@ func (m *Map[K, V]) Delete(key K) (old V, found bool) {
 	}
 }
 
+// DeleteFunc deletes all entries for which fn returns true and returns the number of deleted entries.
+// it runs in a single pass over the table, re-placing entries of clusters that lost members.
+func (m *Map[K, V]) DeleteFunc(fn func(K, V) bool) int {
+	m.finishMigrate()
+
+	start := -1
+	for i := 0; i < m.capacity; i += 1 {
+		if m.getBucket(i).state == stateEmpty {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		// no cluster boundary in a completely full table, rebuild it from the survivors
+		type entry struct {
+			key   K
+			value V
+		}
+		kept := make([]entry, 0, m.count)
+		for i := 0; i < m.capacity; i += 1 {
+			b := m.getBucket(i)
+			if fn(b.key, b.value) != true {
+				kept = append(kept, entry{b.key, b.value})
+			}
+			*b = bucket[K, V]{}
+		}
+		deleted := m.count - len(kept)
+		m.count = 0
+		for _, e := range kept {
+			m.insertRaw(e.key, e.value)
+			m.count += 1
+		}
+		if 0 < deleted {
+			m.shrinkIfSparse()
+		}
+		return deleted
+	}
+
+	deleted := 0
+	dirty := false // current cluster has holes
+	for n := 1; n <= m.capacity; n += 1 {
+		idx := (start + n) & (m.capacity - 1)
+		b := m.getBucket(idx)
+		if b.state == stateEmpty {
+			dirty = false
+			continue
+		}
+		if fn(b.key, b.value) {
+			*b = bucket[K, V]{}
+			m.count -= 1
+			deleted += 1
+			dirty = true
+			continue
+		}
+		if dirty {
+			// entry may now be reachable closer to its ideal position
+			e := *b
+			*b = bucket[K, V]{}
+			m.insertRaw(e.key, e.value)
+		}
+	}
+	if 0 < deleted {
+		m.shrinkIfSparse()
+	}
+	return deleted
+}
+
+// Pop removes and returns an arbitrary entry.
+// while migrating it takes entries of the old table, otherwise the tail of a cluster so that no entries need to be shifted back.
+func (m *Map[K, V]) Pop() (key K, value V, found bool) {
+	if m.count == 0 {
+		return
+	}
+	m.migrate(m.migrateStep)
+
+	if m.migrating() {
+		// old table keeps tombstones, so any entry not yet migrated can be taken
+		for i := m.migrateIdx; i < m.oldCapacity; i += 1 {
+			b := m.getOldBucket(i)
+			if b.state != stateUsed {
+				continue
+			}
+			key, value = b.key, b.value
+			m.count -= 1
+			m.markMoved(b)
+			m.shrinkIfSparse()
+			return key, value, true
+		}
+	}
+
+	mask := m.capacity - 1
+	for n := 0; n < m.capacity; n += 1 {
+		idx := (m.popIdx + n) & mask
+		b := m.getBucket(idx)
+		if b.state != stateUsed {
+			continue
+		}
+		if m.getBucket((idx+1)&mask).state != stateEmpty {
+			continue
+		}
+		key, value = b.key, b.value
+		*b = bucket[K, V]{}
+		m.count -= 1
+		m.popIdx = (idx - 1) & mask // previous bucket is now the tail of its cluster
+		m.shrinkIfSparse()
+		return key, value, true
+	}
+
+	// completely full table has no cluster tail, re-place everything after the hole
+	idx := m.popIdx & mask
+	b := m.getBucket(idx)
+	key, value = b.key, b.value
+	*b = bucket[K, V]{}
+	m.count -= 1
+	for n := 1; n < m.capacity; n += 1 {
+		nb := m.getBucket((idx + n) & mask)
+		e := *nb
+		*nb = bucket[K, V]{}
+		m.insertRaw(e.key, e.value)
+	}
+	m.shrinkIfSparse()
+	return key, value, true
+}
+
+// randomProbes is the number of random bucket picks before Random falls back to a linear walk
+const randomProbes = 32
+
+func (m *Map[K, V]) slots() int {
+	return m.oldCapacity + m.capacity
+}
+
+// slotBucket addresses the old table (while migrating) followed by the current table
+func (m *Map[K, V]) slotBucket(slot int) *bucket[K, V] {
+	if slot < m.oldCapacity {
+		return m.getOldBucket(slot)
+	}
+	return m.getBucket(slot - m.oldCapacity)
+}
+
+// Random returns a random entry without removing it.
+// it picks random buckets until it hits an occupied one, and walks forward from a random bucket as a fallback.
+// the distribution is uniform unless the fallback is taken.
+func (m *Map[K, V]) Random(rng *rand.Rand) (key K, value V, found bool) {
+	if m.count == 0 {
+		return
+	}
+
+	slots := m.slots()
+	for i := 0; i < randomProbes; i += 1 {
+		b := m.slotBucket(rng.IntN(slots))
+		if b.state == stateUsed {
+			return b.key, b.value, true
+		}
+	}
+	start := rng.IntN(slots)
+	for n := 0; n < slots; n += 1 {
+		b := m.slotBucket((start + n) % slots)
+		if b.state == stateUsed {
+			return b.key, b.value, true
+		}
+	}
+	return
+}
+
+// Sample returns up to n distinct keys chosen at random.
+// small samples are drawn by picking random buckets, larger ones by reservoir sampling over all entries.
+func (m *Map[K, V]) Sample(n int, rng *rand.Rand) []K {
+	if n < 1 || m.count == 0 {
+		return nil
+	}
+	if m.count <= n {
+		keys := make([]K, 0, m.count)
+		m.Scan(func(k K, v V) bool {
+			keys = append(keys, k)
+			return true
+		})
+		rng.Shuffle(len(keys), func(i, j int) {
+			keys[i], keys[j] = keys[j], keys[i]
+		})
+		return keys
+	}
+
+	keys := make([]K, 0, n)
+	if n*2 <= m.count {
+		slots := m.slots()
+		seen := make(map[int]struct{}, n)
+		for i := 0; i < slots*2 && len(keys) < n; i += 1 {
+			slot := rng.IntN(slots)
+			if _, ok := seen[slot]; ok {
+				continue
+			}
+			b := m.slotBucket(slot)
+			if b.state != stateUsed {
+				continue
+			}
+			seen[slot] = struct{}{}
+			keys = append(keys, b.key)
+		}
+		if len(keys) == n {
+			return keys
+		}
+		keys = keys[:0]
+	}
+
+	i := 0
+	m.Scan(func(k K, v V) bool {
+		if len(keys) < n {
+			keys = append(keys, k)
+		} else if j := rng.IntN(i + 1); j < n {
+			keys[j] = k
+		}
+		i += 1
+		return true
+	})
+	return keys
+}
+
 func (m *Map[K, V]) shiftBack(idx int) {
 	// Linear probing backward shift deletion
 	curr := idx
@@ -149,8 +678,8 @@ func (m *Map[K, V]) shiftBack(idx int) {
 		next := (curr + 1) & (m.capacity - 1)
 		bNext := m.getBucket(next)
 
-		if bNext.state == stateEmpty {
-			// Found empty slot, clear current and return
+		if bNext.state == stateEmpty || next == curr {
+			// Found empty slot (or wrapped around a single bucket table), clear current and return
 			bCurr := m.getBucket(curr)
 			bCurr.state = stateEmpty
 			var zeroK K
@@ -237,8 +766,8 @@ func (m *Map[K, V]) shiftBack(idx int) {
 			scan := (curr + 1) & (m.capacity - 1)
 			for {
 				bScan := m.getBucket(scan)
-				if bScan.state == stateEmpty {
-					// End of cluster, clear hole and done
+				if bScan.state == stateEmpty || scan == curr {
+					// End of cluster (or wrapped around a completely full table), clear hole and done
 					bCurr := m.getBucket(curr)
 					bCurr.state = stateEmpty
 					var zeroK K
@@ -282,7 +811,115 @@ func (m *Map[K, V]) shiftBack(idx int) {
 	}
 }
 
+// Shrink resizes the table to the smallest power of two that fits Len() under the load factor
+func (m *Map[K, V]) Shrink() {
+	if m.fixed {
+		return
+	}
+	capacity := m.fitCapacity(m.count)
+	if capacity < m.capacity {
+		m.resize(capacity)
+	}
+}
+
+// fitCapacity returns the smallest power of two that holds n entries under the load factor
+// while leaving at least one bucket empty
+func (m *Map[K, V]) fitCapacity(n int) int {
+	capacity := 1
+	for capacity <= n || m.loadFactor < (float64(n)/float64(capacity)) {
+		capacity *= 2
+	}
+	return capacity
+}
+
+func (m *Map[K, V]) shrinkIfSparse() {
+	if m.fixed || m.minLoadFactor <= 0 || m.capacity <= m.minCapacity {
+		return
+	}
+	if m.minLoadFactor <= (float64(m.count) / float64(m.capacity)) {
+		return
+	}
+	capacity := max(m.fitCapacity(m.count)*2, m.minCapacity)
+	if capacity < m.capacity {
+		m.startResize(capacity)
+	}
+}
+
+func (m *Map[K, V]) migrating() bool {
+	return m.oldBuckets != nil
+}
+
+func (m *Map[K, V]) findOld(hash uint64, key K) (int, bool) {
+	mask := m.oldCapacity - 1
+	idx := int(hash) & mask
+	startIdx := idx
+	for {
+		b := m.getOldBucket(idx)
+		if b.state == stateEmpty {
+			return -1, false
+		}
+		if b.state == stateUsed && b.key == key {
+			return idx, true
+		}
+		idx = (idx + 1) & mask
+		if idx == startIdx {
+			return -1, false
+		}
+	}
+}
+
+// markMoved leaves a tombstone so that probe chains in the old table stay intact
+func (m *Map[K, V]) markMoved(b *bucket[K, V]) {
+	var zeroK K
+	var zeroV V
+	b.key = zeroK
+	b.value = zeroV
+	b.state = stateMoved
+}
+
+// migrate moves up to n buckets from the old table into the current table
+func (m *Map[K, V]) migrate(n int) {
+	if m.migrating() != true {
+		return
+	}
+	for i := 0; i < n && m.migrateIdx < m.oldCapacity; i += 1 {
+		b := m.getOldBucket(m.migrateIdx)
+		if b.state == stateUsed {
+			m.insertRaw(b.key, b.value)
+			m.markMoved(b)
+		}
+		m.migrateIdx += 1
+	}
+	if m.oldCapacity <= m.migrateIdx {
+		m.oldBuckets = nil
+		m.oldCapacity = 0
+		m.migrateIdx = 0
+	}
+}
+
+func (m *Map[K, V]) finishMigrate() {
+	m.migrate(m.oldCapacity)
+}
+
+// startResize resizes the table, keeping the old table for incremental migration if enabled
+func (m *Map[K, V]) startResize(newCapacity int) {
+	if m.migrateStep < 1 || m.capacity == 0 {
+		m.resize(newCapacity)
+		return
+	}
+	m.finishMigrate()
+
+	m.oldBuckets = m.buckets
+	m.oldCapacity = m.capacity
+	m.migrateIdx = 0
+
+	m.capacity = newCapacity
+	m.buckets = make([]byte, uintptr(newCapacity)*m.bucketSize)
+}
+
 func (m *Map[K, V]) resize(newCapacity int) {
+	m.finishMigrate()
+
 	oldBuckets := m.buckets
 	oldCapacity := m.capacity // Save old capacity before updating
 
@@ -294,8 +931,6 @@ func (m *Map[K, V]) resize(newCapacity int) {
 	totalSize := uintptr(newCapacity) * m.bucketSize
 	m.buckets = make([]byte, totalSize)
 
-	m.count = 0
-
 	if 0 < oldCapacity {
 		oldBucketSize := m.bucketSize
 		for i := 0; i < oldCapacity; i += 1 {
@@ -316,19 +951,23 @@ func (m *Map[K, V]) insertRaw(key K, value V) {
 			b.key = key
 			b.value = value
 			b.state = stateUsed
-			m.count += 1
 			return
 		}
 		idx = (idx + 1) & (m.capacity - 1)
 	}
 }
 
+// Clear removes all entries and returns the table to its initial capacity
 func (m *Map[K, V]) Clear() {
 	var b bucket[K, V]
 	m.bucketSize = unsafe.Sizeof(b)
+	m.capacity = m.minCapacity
 	totalSize := uintptr(m.capacity) * m.bucketSize
 	m.buckets = make([]byte, totalSize)
 	m.count = 0
+	m.oldBuckets = nil
+	m.oldCapacity = 0
+	m.migrateIdx = 0
 }
 
 func NewMap[K comparable, V any](arena Arena, funcs ...OptionFunc) *Map[K, V] {
@@ -344,12 +983,24 @@ func NewMap[K comparable, V any](arena Arena, funcs ...OptionFunc) *Map[K, V] {
 	for capacity < opt.capacity {
 		capacity *= 2
 	}
+	if opt.fixed {
+		// preallocate so that opt.capacity entries fit under the load factor
+		for opt.loadFactor < (float64(opt.capacity) / float64(capacity)) {
+			capacity *= 2
+		}
+	}
 
 	m := &Map[K, V]{
-		arena:      arena,
-		hasher:     maphash.NewHasher[K](),
-		capacity:   0, // Initialize to 0 so resize treats it as fresh
-		loadFactor: opt.loadFactor,
+		arena:       arena,
+		hasher:      newHasher[K](opt),
+		capacity:    0, // Initialize to 0 so resize treats it as fresh
+		loadFactor:  opt.loadFactor,
+		migrateStep: opt.migrateStep,
+
+		minCapacity:   capacity,
+		minLoadFactor: opt.minLoadFactor,
+		fixed:         opt.fixed,
+		valuePointers: hasPointers[V](),
 	}
 	m.resize(capacity)
 	return m
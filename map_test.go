@@ -1,10 +1,16 @@
 package armap
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"math/rand/v2"
+	"runtime"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
+	"unsafe"
 )
 
 func TestMap(t *testing.T) {
@@ -248,4 +254,678 @@ func TestMap(t *testing.T) {
 
 		NewMap[string, PrivateStruct](a)
 	})
+
+	t.Run("incremental_resize", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a, WithCapacity(16), WithIncrementalResize(2))
+
+		N := 1000
+		for i := 0; i < N; i += 1 {
+			m.Set(i, i)
+			if i%3 == 0 {
+				if _, ok := m.Get(i / 2); ok != true {
+					tt.Errorf("key %d exists while migrating", i/2)
+				}
+			}
+		}
+		if m.migrating() != true {
+			tt.Errorf("expect migrating")
+		}
+		if m.Len() != N {
+			tt.Errorf("len = %d, expect %d", m.Len(), N)
+		}
+
+		count := 0
+		m.Scan(func(k, v int) bool {
+			if k != v {
+				tt.Errorf("key %d value %d", k, v)
+			}
+			count += 1
+			return true
+		})
+		if count != N {
+			tt.Errorf("scan count = %d, expect %d", count, N)
+		}
+
+		for i := 0; i < N; i += 2 {
+			if _, ok := m.Delete(i); ok != true {
+				tt.Errorf("key %d exists", i)
+			}
+		}
+		for i := 1; i < N; i += 2 {
+			if old, ok := m.Set(i, i*10); ok != true || old != i {
+				tt.Errorf("key %d old value = %d", i, old)
+			}
+		}
+		for i := 0; i < N; i += 1 {
+			v, ok := m.Get(i)
+			if i%2 == 0 {
+				if ok {
+					tt.Errorf("key %d deleted", i)
+				}
+				continue
+			}
+			if ok != true || v != i*10 {
+				tt.Errorf("key %d value = %d", i, v)
+			}
+		}
+		if m.Len() != N/2 {
+			tt.Errorf("len = %d, expect %d", m.Len(), N/2)
+		}
+	})
+
+	t.Run("Shrink", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a, WithCapacity(16))
+
+		for i := 0; i < 10_000; i += 1 {
+			m.Set(i, i)
+		}
+		for i := 100; i < 10_000; i += 1 {
+			m.Delete(i)
+		}
+		m.Shrink()
+		if m.capacity != 128 {
+			tt.Errorf("capacity = %d, expect 128", m.capacity)
+		}
+		for i := 0; i < 100; i += 1 {
+			if v, ok := m.Get(i); ok != true || v != i {
+				tt.Errorf("key %d value = %d", i, v)
+			}
+		}
+
+		m.Clear()
+		m.Shrink()
+		if m.capacity != 1 {
+			tt.Errorf("capacity = %d, expect 1", m.capacity)
+		}
+		m.Set(1, 1)
+		if v, ok := m.Get(1); ok != true || v != 1 {
+			tt.Errorf("key 1 value = %d", v)
+		}
+		if _, ok := m.Delete(1); ok != true {
+			tt.Errorf("key 1 exists")
+		}
+		if m.Len() != 0 {
+			tt.Errorf("len = %d, expect 0", m.Len())
+		}
+	})
+
+	t.Run("Delete/full_table", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		r := rand.New(rand.NewPCG(7, 8))
+		for _, capacity := range []int{1, 2, 16, 256} {
+			m := NewMap[int, int](a, WithCapacity(capacity), WithLoadFactor(1.0))
+			for i := 0; i < capacity; i += 1 {
+				m.Set(i, i)
+			}
+			if m.capacity != capacity {
+				tt.Fatalf("capacity = %d, expect %d", m.capacity, capacity)
+			}
+			keys := r.Perm(capacity)
+			for n, k := range keys {
+				if v, ok := m.Delete(k); ok != true || v != k {
+					tt.Errorf("cap=%d delete %d = %d, %v", capacity, k, v, ok)
+				}
+				for _, rest := range keys[n+1:] {
+					if _, ok := m.Get(rest); ok != true {
+						tt.Errorf("cap=%d key %d lost", capacity, rest)
+					}
+				}
+			}
+			if m.Len() != 0 {
+				tt.Errorf("cap=%d len = %d, expect 0", capacity, m.Len())
+			}
+		}
+	})
+
+	t.Run("Clear", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a, WithCapacity(16))
+
+		for i := 0; i < 10_000; i += 1 {
+			m.Set(i, i)
+		}
+		m.Clear()
+		if m.capacity != 16 {
+			tt.Errorf("capacity = %d, expect 16", m.capacity)
+		}
+		if m.Len() != 0 {
+			tt.Errorf("len = %d, expect 0", m.Len())
+		}
+		m.Set(1, 1)
+		if v, ok := m.Get(1); ok != true || v != 1 {
+			tt.Errorf("key 1 value = %d", v)
+		}
+	})
+
+	t.Run("WithMinLoadFactor", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a, WithCapacity(16), WithMinLoadFactor(0.2))
+
+		for i := 0; i < 10_000; i += 1 {
+			m.Set(i, i)
+		}
+		grown := m.capacity
+		for i := 0; i < 9_990; i += 1 {
+			m.Delete(i)
+		}
+		if grown <= m.capacity {
+			tt.Errorf("capacity = %d, expect shrink from %d", m.capacity, grown)
+		}
+		if m.capacity < 16 {
+			tt.Errorf("capacity = %d, expect >= initial capacity", m.capacity)
+		}
+		for i := 9_990; i < 10_000; i += 1 {
+			if v, ok := m.Get(i); ok != true || v != i {
+				tt.Errorf("key %d value = %d", i, v)
+			}
+		}
+	})
+
+	t.Run("Grow", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a, WithCapacity(16))
+
+		m.Set(0, 0)
+		m.Grow(1000)
+		if m.capacity != 2048 {
+			tt.Errorf("capacity = %d, expect 2048", m.capacity)
+		}
+		for i := 1; i <= 1000; i += 1 {
+			m.Set(i, i)
+		}
+		if m.capacity != 2048 {
+			tt.Errorf("capacity = %d, expect no resize", m.capacity)
+		}
+	})
+
+	t.Run("BulkLoad", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[string, int](a, WithCapacity(16))
+
+		src := make(map[string]int)
+		for i := 0; i < 1000; i += 1 {
+			src[strconv.Itoa(i)] = i
+		}
+		m.BulkLoad(maps.All(src))
+
+		if m.Len() != len(src) {
+			tt.Errorf("len = %d, expect %d", m.Len(), len(src))
+		}
+		if m.capacity != 2048 {
+			tt.Errorf("capacity = %d, expect 2048", m.capacity)
+		}
+		for k, v := range src {
+			if actual, ok := m.Get(k); ok != true || actual != v {
+				tt.Errorf("key %s value = %d", k, actual)
+			}
+		}
+	})
+
+	t.Run("BulkLoad/batches", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a)
+
+		n := bulkLoadBatch*3 + 10
+		m.BulkLoad(func(yield func(int, int) bool) {
+			for i := 0; i < n; i += 1 {
+				if yield(i, i) != true {
+					return
+				}
+			}
+			// overwrite keys of the first batch
+			for i := 0; i < 10; i += 1 {
+				if yield(i, -i) != true {
+					return
+				}
+			}
+		})
+
+		if m.Len() != n {
+			tt.Errorf("len = %d, expect %d", m.Len(), n)
+		}
+		for i := 0; i < n; i += 1 {
+			expect := i
+			if i < 10 {
+				expect = -i
+			}
+			if v, ok := m.Get(i); ok != true || v != expect {
+				tt.Errorf("key %d value = %d, expect %d", i, v, expect)
+			}
+		}
+	})
+
+	t.Run("BulkLoad/single_use", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a)
+
+		ch := make(chan int, 10)
+		for i := 0; i < 10; i += 1 {
+			ch <- i
+		}
+		close(ch)
+		m.BulkLoad(func(yield func(int, int) bool) {
+			for i := range ch {
+				if yield(i, i*10) != true {
+					return
+				}
+			}
+		})
+
+		if m.Len() != 10 {
+			tt.Errorf("len = %d, expect 10", m.Len())
+		}
+		for i := 0; i < 10; i += 1 {
+			if v, ok := m.Get(i); ok != true || v != i*10 {
+				tt.Errorf("key %d value = %d", i, v)
+			}
+		}
+	})
+
+	t.Run("WithFixedCapacity", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a, WithFixedCapacity(100), WithLoadFactor(0.5))
+
+		capacity := m.capacity
+		if capacity != 256 {
+			tt.Errorf("capacity = %d, expect 256", capacity)
+		}
+		i := 0
+		for ; ; i += 1 {
+			if _, _, err := m.TrySet(i, i); err != nil {
+				if errors.Is(err, ErrFull) != true {
+					tt.Errorf("unexpected error: %+v", err)
+				}
+				break
+			}
+		}
+		if i != 128 {
+			tt.Errorf("inserted = %d, expect 128", i)
+		}
+		if m.capacity != capacity {
+			tt.Errorf("capacity = %d, expect no resize", m.capacity)
+		}
+
+		// update existing key on full table
+		if old, found, err := m.TrySet(0, 100); err != nil || found != true || old != 0 {
+			tt.Errorf("update: old=%d found=%v err=%+v", old, found, err)
+		}
+
+		// Set does not insert into full table
+		if _, found := m.Set(-1, -1); found {
+			tt.Errorf("-1 is new key")
+		}
+		if _, ok := m.Get(-1); ok {
+			tt.Errorf("-1 must not be inserted")
+		}
+		if m.Len() != 128 {
+			tt.Errorf("len = %d, expect 128", m.Len())
+		}
+		m.SetMany([]int{-2, -3}, []int{-2, -3})
+		if m.Len() != 128 {
+			tt.Errorf("len = %d, expect 128", m.Len())
+		}
+
+		m.Delete(1)
+		if _, _, err := m.TrySet(-1, -1); err != nil {
+			tt.Errorf("unexpected error: %+v", err)
+		}
+	})
+
+	t.Run("Hashed", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m1 := NewMap[string, int](a)
+		m2 := NewMap[string, int](a, WithHasher(m1.Hasher()))
+
+		for i := 0; i < 100; i += 1 {
+			k := strconv.Itoa(i)
+			h := m1.Hash(k)
+			m1.SetHashed(h, k, i)
+			m2.SetHashed(h, k, i*2)
+		}
+		for i := 0; i < 100; i += 1 {
+			k := strconv.Itoa(i)
+			if m1.Hash(k) != m2.Hash(k) {
+				tt.Errorf("key %s hash mismatch", k)
+			}
+			h := m1.Hash(k)
+			if v, ok := m1.GetHashed(h, k); ok != true || v != i {
+				tt.Errorf("m1 key %s value = %d", k, v)
+			}
+			if v, ok := m2.Get(k); ok != true || v != i*2 {
+				tt.Errorf("m2 key %s value = %d", k, v)
+			}
+			if v, ok := m2.DeleteHashed(h, k); ok != true || v != i*2 {
+				tt.Errorf("m2 key %s deleted value = %d", k, v)
+			}
+		}
+		if m2.Len() != 0 {
+			tt.Errorf("len = %d, expect 0", m2.Len())
+		}
+	})
+
+	t.Run("WithHasher/mismatch", func(tt *testing.T) {
+		a := NewArena(1024)
+		defer a.Release()
+		m1 := NewMap[string, int](a)
+
+		defer func() {
+			if r := recover(); r == nil {
+				tt.Errorf("expected panic for hasher type mismatch")
+			}
+		}()
+		NewMap[int, int](a, WithHasher(m1.Hasher()))
+	})
+
+	t.Run("GetPtr/Modify", func(tt *testing.T) {
+		type Stat struct {
+			Count int
+			Sum   int
+		}
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[string, Stat](a)
+
+		if p := m.GetPtr("a"); p != nil {
+			tt.Errorf("key a not exists")
+		}
+		if ok := m.Modify("a", func(s *Stat) {}); ok {
+			tt.Errorf("key a not exists")
+		}
+
+		m.Set("a", Stat{})
+		p := m.GetPtr("a")
+		p.Count = 10
+
+		for i := 1; i <= 10; i += 1 {
+			ok := m.Modify("a", func(s *Stat) {
+				s.Sum += i
+			})
+			if ok != true {
+				tt.Errorf("key a exists")
+			}
+		}
+		if v, _ := m.Get("a"); v.Count != 10 || v.Sum != 55 {
+			tt.Errorf("value = %+v", v)
+		}
+	})
+
+	t.Run("Modify/pointers", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, []int](a)
+		m.Set(1, nil)
+
+		heap := []int{1, 2, 3}
+		m.Modify(1, func(v *[]int) {
+			*v = heap
+		})
+		p := m.GetPtr(1)
+		if slices.Equal(*p, heap) != true {
+			tt.Errorf("value = %v, expect %v", *p, heap)
+		}
+		if unsafe.SliceData(*p) == unsafe.SliceData(heap) {
+			tt.Errorf("assigned slice must be cloned into the arena")
+		}
+		for i := 4; i <= 100; i += 1 {
+			m.Modify(1, func(v *[]int) {
+				*v = append(*v, i)
+			})
+		}
+		runtime.GC()
+		if v, _ := m.Get(1); len(v) != 100 || v[0] != 1 || v[99] != 100 {
+			tt.Errorf("value = %v", v)
+		}
+	})
+
+	t.Run("SetMany/GetMany/DeleteMany", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[string, int](a, WithCapacity(16))
+
+		keys := make([]string, 1000)
+		values := make([]int, 1000)
+		for i := range keys {
+			keys[i] = strconv.Itoa(i)
+			values[i] = i
+		}
+		m.SetMany(keys, values)
+		if m.Len() != 1000 {
+			tt.Errorf("len = %d, expect 1000", m.Len())
+		}
+
+		lookup := []string{"0", "999", "1000", "500"}
+		actual := make([]int, len(lookup))
+		found := m.GetMany(lookup, actual)
+		if slices.Equal(found, []bool{true, true, false, true}) != true {
+			tt.Errorf("found = %v", found)
+		}
+		if actual[0] != 0 || actual[1] != 999 || actual[3] != 500 {
+			tt.Errorf("values = %v", actual)
+		}
+
+		if n := m.DeleteMany([]string{"0", "1", "1000", "1"}); n != 2 {
+			tt.Errorf("deleted = %d, expect 2", n)
+		}
+		if m.Len() != 998 {
+			tt.Errorf("len = %d, expect 998", m.Len())
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					tt.Errorf("expected panic for length mismatch")
+				}
+			}()
+			m.SetMany([]string{"a"}, nil)
+		}()
+	})
+
+	t.Run("SetMany/incremental", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a, WithCapacity(16), WithIncrementalResize(1))
+		for i := 0; i < 1000; i += 1 {
+			m.Set(i, i)
+		}
+		for m.migrating() != true {
+			m.Set(m.Len(), m.Len())
+		}
+		n := m.Len()
+
+		m.SetMany([]int{-1}, []int{-1})
+		if m.migrating() != true {
+			tt.Errorf("SetMany must not finish migration")
+		}
+
+		keys := make([]int, 5000)
+		values := make([]int, 5000)
+		for i := range keys {
+			keys[i] = n + i
+			values[i] = n + i
+		}
+		m.SetMany(keys, values)
+		if m.Len() != n+5001 {
+			tt.Errorf("len = %d, expect %d", m.Len(), n+5001)
+		}
+		for i := -1; i < n+5000; i += 1 {
+			if v, ok := m.Get(i); ok != true || v != i {
+				tt.Errorf("key %d value = %d", i, v)
+			}
+		}
+	})
+
+	t.Run("DeleteFunc", func(tt *testing.T) {
+		for _, lf := range []float64{0.5, 0.95, 1.0} {
+			a := NewArena(1024 * 1024)
+			m := NewMap[int, int](a, WithCapacity(1024), WithLoadFactor(lf))
+
+			n := int(1024 * lf)
+			for i := 0; i < n; i += 1 {
+				m.Set(i*7, i)
+			}
+			deleted := m.DeleteFunc(func(k, v int) bool {
+				return v%3 == 0
+			})
+			expectDeleted := (n + 2) / 3
+			if deleted != expectDeleted {
+				tt.Errorf("lf=%f deleted = %d, expect %d", lf, deleted, expectDeleted)
+			}
+			if m.Len() != n-expectDeleted {
+				tt.Errorf("lf=%f len = %d, expect %d", lf, m.Len(), n-expectDeleted)
+			}
+			for i := 0; i < n; i += 1 {
+				v, ok := m.Get(i * 7)
+				if i%3 == 0 {
+					if ok {
+						tt.Errorf("lf=%f key %d is deleted", lf, i*7)
+					}
+					continue
+				}
+				if ok != true || v != i {
+					tt.Errorf("lf=%f key %d value = %d", lf, i*7, v)
+				}
+			}
+			a.Release()
+		}
+	})
+
+	t.Run("Pop", func(tt *testing.T) {
+		for _, lf := range []float64{0.5, 1.0} {
+			a := NewArena(1024 * 1024)
+			m := NewMap[int, int](a, WithCapacity(256), WithLoadFactor(lf))
+			n := int(256 * lf)
+			for i := 0; i < n; i += 1 {
+				m.Set(i, i*10)
+			}
+			seen := make(map[int]bool, n)
+			for i := 0; i < n; i += 1 {
+				k, v, ok := m.Pop()
+				if ok != true {
+					tt.Fatalf("lf=%f pop %d not found", lf, i)
+				}
+				if v != k*10 {
+					tt.Errorf("lf=%f key %d value = %d, expect %d", lf, k, v, k*10)
+				}
+				if seen[k] {
+					tt.Errorf("lf=%f key %d popped twice", lf, k)
+				}
+				seen[k] = true
+				if _, ok := m.Get(k); ok {
+					tt.Errorf("lf=%f key %d still exists", lf, k)
+				}
+				if m.Len() != n-i-1 {
+					tt.Errorf("lf=%f len = %d, expect %d", lf, m.Len(), n-i-1)
+				}
+			}
+			if _, _, ok := m.Pop(); ok {
+				tt.Errorf("lf=%f pop from empty map", lf)
+			}
+			a.Release()
+		}
+	})
+	t.Run("Pop/incremental", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMap[int, int](a, WithCapacity(16), WithIncrementalResize(1))
+		for i := 0; i < 1000; i += 1 {
+			m.Set(i, i)
+		}
+		for m.migrating() != true {
+			m.Set(m.Len(), m.Len())
+		}
+		n := m.Len()
+
+		if _, _, ok := m.Pop(); ok != true {
+			tt.Fatalf("pop not found")
+		}
+		if m.migrating() != true {
+			tt.Errorf("pop must not finish migration")
+		}
+		seen := make(map[int]bool, n)
+		for i := 1; i < n; i += 1 {
+			k, v, ok := m.Pop()
+			if ok != true || k != v || seen[k] {
+				tt.Fatalf("pop = %d, %d, %v", k, v, ok)
+			}
+			seen[k] = true
+			if _, ok := m.Get(k); ok {
+				tt.Errorf("key %d still exists", k)
+			}
+		}
+		if m.Len() != 0 {
+			tt.Errorf("len = %d, expect 0", m.Len())
+		}
+	})
+
+	t.Run("Random", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		rng := rand.New(rand.NewPCG(1, 2))
+
+		m := NewMap[int, int](a, WithCapacity(16))
+		if _, _, ok := m.Random(rng); ok {
+			tt.Errorf("random from empty map")
+		}
+		for i := 0; i < 10; i += 1 {
+			m.Set(i, i*10)
+		}
+		counts := make([]int, 10)
+		for i := 0; i < 10000; i += 1 {
+			k, v, ok := m.Random(rng)
+			if ok != true {
+				tt.Fatalf("random not found")
+			}
+			if v != k*10 {
+				tt.Errorf("key %d value = %d, expect %d", k, v, k*10)
+			}
+			counts[k] += 1
+		}
+		for k, c := range counts {
+			if c < 500 {
+				tt.Errorf("key %d picked %d times", k, c)
+			}
+		}
+	})
+	t.Run("Sample", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		rng := rand.New(rand.NewPCG(3, 4))
+
+		for _, inc := range []int{0, 1} {
+			m := NewMap[int, int](a, WithCapacity(16), WithIncrementalResize(inc))
+			for i := 0; i < 1000; i += 1 {
+				m.Set(i, i)
+			}
+			for _, n := range []int{0, 1, 10, 400, 700, 1000, 2000} {
+				keys := m.Sample(n, rng)
+				expect := min(n, 1000)
+				if len(keys) != expect {
+					tt.Errorf("inc=%d sample(%d) len = %d, expect %d", inc, n, len(keys), expect)
+				}
+				uniq := make(map[int]struct{}, len(keys))
+				for _, k := range keys {
+					if k < 0 || 1000 <= k {
+						tt.Errorf("inc=%d sample(%d) unknown key %d", inc, n, k)
+					}
+					uniq[k] = struct{}{}
+				}
+				if len(uniq) != len(keys) {
+					tt.Errorf("inc=%d sample(%d) has duplicates", inc, n)
+				}
+			}
+		}
+	})
 }
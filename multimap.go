@@ -0,0 +1,171 @@
+package armap
+
+import (
+	"iter"
+	"unsafe"
+)
+
+// multiChunk is a fixed capacity piece of a per-key value list
+type multiChunk[V any] struct {
+	values []V
+	next   *multiChunk[V]
+}
+
+// multiList is a chain of chunks, chunk capacity doubles up to the arena buffer so that
+// growing a list never copies or abandons existing values.
+// fields are exported so that TypeArena can clone the entry
+type multiList[V any] struct {
+	Head  *multiChunk[V]
+	Tail  *multiChunk[V]
+	Count int
+}
+
+// MultiMap holds many values per key, per-key value lists are allocated in the arena.
+type MultiMap[K comparable, V comparable] struct {
+	m        *Map[K, multiList[V]]
+	va       TypeArena[V]
+	ca       TypeArena[multiChunk[V]]
+	maxChunk int
+	count    int
+}
+
+// Len returns the total number of values
+func (mm *MultiMap[K, V]) Len() int {
+	return mm.count
+}
+
+// Add appends value to key.
+// a new key is not inserted when the MultiMap created by WithFixedCapacity is full; use TryAdd to detect it.
+func (mm *MultiMap[K, V]) Add(key K, value V) {
+	mm.TryAdd(key, value)
+}
+
+// TryAdd is Add that returns ErrFull when key is new and the MultiMap created by WithFixedCapacity is full
+func (mm *MultiMap[K, V]) TryAdd(key K, value V) error {
+	b, _, err := mm.m.entry(mm.m.Hash(key), key)
+	if err != nil {
+		return err
+	}
+	l := &b.value
+	if l.Tail == nil {
+		l.Head = mm.newChunk(8)
+		l.Tail = l.Head
+	}
+	if len(l.Tail.values) == cap(l.Tail.values) {
+		if l.Tail.next == nil {
+			l.Tail.next = mm.newChunk(cap(l.Tail.values) * 2)
+		}
+		l.Tail = l.Tail.next
+	}
+	l.Tail.values = append(l.Tail.values, mm.va.Clone(value))
+	l.Count += 1
+	mm.count += 1
+	return nil
+}
+
+func (mm *MultiMap[K, V]) newChunk(size int) *multiChunk[V] {
+	c := mm.ca.New()
+	// capacity of 8 or more keeps the arena pointer aligned for any element size
+	c.values = mm.va.MakeSlice(0, min(size, mm.maxChunk))
+	return c
+}
+
+func (mm *MultiMap[K, V]) Get(key K) iter.Seq[V] {
+	l, _ := mm.m.Get(key)
+	return func(yield func(V) bool) {
+		for c := l.Head; c != nil; c = c.next {
+			for _, v := range c.values {
+				if yield(v) != true {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (mm *MultiMap[K, V]) Count(key K) int {
+	l, _ := mm.m.Get(key)
+	return l.Count
+}
+
+// DeleteValue removes the first occurrence of value from key, and key itself when no value remains
+func (mm *MultiMap[K, V]) DeleteValue(key K, value V) bool {
+	h := mm.m.Hash(key)
+	b := mm.m.find(h, key)
+	if b == nil {
+		return false
+	}
+	l := &b.value
+	for c := l.Head; c != nil; c = c.next {
+		for i, v := range c.values {
+			if v != value {
+				continue
+			}
+			l.remove(c, i)
+			mm.count -= 1
+			if l.Count == 0 {
+				mm.m.DeleteHashed(h, key)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// remove deletes c.values[i], shifting the following values of the chain back by one
+func (l *multiList[V]) remove(c *multiChunk[V], i int) {
+	for {
+		copy(c.values[i:], c.values[i+1:])
+		last := len(c.values) - 1
+		if c.next != nil && 0 < len(c.next.values) {
+			c.values[last] = c.next.values[0]
+			c, i = c.next, 0
+			continue
+		}
+		var zero V
+		c.values[last] = zero
+		c.values = c.values[:last]
+		break
+	}
+	// keep Tail at the last non-empty chunk, empty chunks after it are reused by Add
+	if len(l.Tail.values) == 0 && l.Tail != l.Head {
+		t := l.Head
+		for t.next != l.Tail {
+			t = t.next
+		}
+		l.Tail = t
+	}
+	l.Count -= 1
+}
+
+// DeleteKey removes key and returns the number of values removed
+func (mm *MultiMap[K, V]) DeleteKey(key K) int {
+	l, ok := mm.m.Delete(key)
+	if ok != true {
+		return 0
+	}
+	mm.count -= l.Count
+	return l.Count
+}
+
+func (mm *MultiMap[K, V]) Clear() {
+	mm.m.Clear()
+	mm.count = 0
+}
+
+func NewMultiMap[K comparable, V comparable](arena Arena, funcs ...OptionFunc) *MultiMap[K, V] {
+	var v V
+	size := max(int(unsafe.Sizeof(v)), 1)
+	// largest power of two chunk that fits into one arena buffer
+	maxChunk := 1
+	for maxChunk*2*size <= arena.chunkSize() {
+		maxChunk *= 2
+	}
+	return &MultiMap[K, V]{
+		m:        NewMap[K, multiList[V]](arena, funcs...),
+		va:       NewTypeArena[V](arena),
+		ca:       NewTypeArena[multiChunk[V]](arena),
+		maxChunk: maxChunk,
+		count:    0,
+	}
+}
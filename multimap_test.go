@@ -0,0 +1,130 @@
+package armap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMultiMap(t *testing.T) {
+	a := NewArena(1024 * 1024)
+	defer a.Release()
+	m := NewMultiMap[string, int](a)
+
+	for i := 0; i < 10; i += 1 {
+		m.Add("even", i*2)
+		m.Add("odd", i*2+1)
+	}
+	m.Add("one", 1)
+
+	if m.Len() != 21 {
+		t.Errorf("len = %d, expect 21", m.Len())
+	}
+	if c := m.Count("even"); c != 10 {
+		t.Errorf("count(even) = %d, expect 10", c)
+	}
+	if c := m.Count("none"); c != 0 {
+		t.Errorf("count(none) = %d, expect 0", c)
+	}
+
+	evens := slices.Collect(m.Get("even"))
+	if slices.Equal(evens, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}) != true {
+		t.Errorf("get(even) = %v", evens)
+	}
+
+	if ok := m.DeleteValue("even", 4); ok != true {
+		t.Errorf("4 exists in even")
+	}
+	if ok := m.DeleteValue("even", 5); ok {
+		t.Errorf("5 not exists in even")
+	}
+	evens = slices.Collect(m.Get("even"))
+	if slices.Equal(evens, []int{0, 2, 6, 8, 10, 12, 14, 16, 18}) != true {
+		t.Errorf("get(even) = %v", evens)
+	}
+
+	if ok := m.DeleteValue("one", 1); ok != true {
+		t.Errorf("1 exists in one")
+	}
+	if c := m.Count("one"); c != 0 {
+		t.Errorf("count(one) = %d, expect 0", c)
+	}
+
+	if n := m.DeleteKey("odd"); n != 10 {
+		t.Errorf("delete odd = %d, expect 10", n)
+	}
+	if m.Len() != 9 {
+		t.Errorf("len = %d, expect 9", m.Len())
+	}
+
+	t.Run("exceed_buffer", func(tt *testing.T) {
+		a := NewArena(4 * 1024)
+		defer a.Release()
+		m := NewMultiMap[int, int](a)
+
+		for i := 0; i < 2000; i += 1 {
+			m.Add(i%50, i)
+		}
+		for k := 0; k < 50; k += 1 {
+			expect := make([]int, 0, 40)
+			for i := k; i < 2000; i += 50 {
+				expect = append(expect, i)
+			}
+			if values := slices.Collect(m.Get(k)); slices.Equal(values, expect) != true {
+				tt.Errorf("get(%d) = %v, expect %v", k, values, expect)
+			}
+		}
+	})
+
+	t.Run("WithFixedCapacity", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewMultiMap[int, int](a, WithFixedCapacity(8))
+
+		i := 0
+		for ; ; i += 1 {
+			if err := m.TryAdd(i, i); err != nil {
+				if errors.Is(err, ErrFull) != true {
+					tt.Errorf("unexpected error: %+v", err)
+				}
+				break
+			}
+		}
+		m.Add(-1, -1)
+		m.Add(0, 1)
+		if m.Count(-1) != 0 || m.Count(0) != 2 {
+			tt.Errorf("count(-1) = %d, count(0) = %d", m.Count(-1), m.Count(0))
+		}
+		if m.Len() != i+1 {
+			tt.Errorf("len = %d, expect %d", m.Len(), i+1)
+		}
+	})
+
+	t.Run("exceed_buffer/one_key", func(tt *testing.T) {
+		// a single value list larger than the arena buffer
+		a := NewArena(4 * 1024)
+		defer a.Release()
+		m := NewMultiMap[int, int](a)
+
+		expect := make([]int, 0, 10_000)
+		for i := 0; i < 10_000; i += 1 {
+			m.Add(1, i)
+			expect = append(expect, i)
+		}
+		for _, v := range []int{0, 7, 8, 500, 5000, 9999} {
+			if m.DeleteValue(1, v) != true {
+				tt.Errorf("%d exists", v)
+			}
+			expect = slices.DeleteFunc(expect, func(e int) bool { return e == v })
+		}
+		m.Add(1, -1)
+		expect = append(expect, -1)
+
+		if values := slices.Collect(m.Get(1)); slices.Equal(values, expect) != true {
+			tt.Errorf("get(1) mismatch, len = %d, expect %d", len(values), len(expect))
+		}
+		if c := m.Count(1); c != len(expect) {
+			tt.Errorf("count = %d, expect %d", c, len(expect))
+		}
+	})
+}
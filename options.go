@@ -1,9 +1,21 @@
 package armap
 
+import (
+	"fmt"
+
+	"github.com/dolthub/maphash"
+)
+
 type OptionFunc func(*option)
 type option struct {
-	capacity   int
-	loadFactor float64
+	capacity    int
+	loadFactor  float64
+	migrateStep int
+
+	minLoadFactor float64
+	fixed         bool
+	hasher        any
+	overwrite     bool
 }
 
 func WithCapacity(size int) OptionFunc {
@@ -18,9 +30,71 @@ func WithLoadFactor(rate float64) OptionFunc {
 	}
 }
 
+// WithIncrementalResize spreads rehashing over subsequent Set/Delete calls,
+// migrating up to step buckets per call instead of rehashing all at once.
+func WithIncrementalResize(step int) OptionFunc {
+	return func(opt *option) {
+		opt.migrateStep = step
+	}
+}
+
+// WithMinLoadFactor shrinks the table on Delete when the load drops below rate.
+// the table never shrinks below the initial capacity.
+func WithMinLoadFactor(rate float64) OptionFunc {
+	return func(opt *option) {
+		opt.minLoadFactor = rate
+	}
+}
+
+// WithFixedCapacity preallocates a table for size entries that never resizes.
+// new keys beyond the load limit are not inserted, Try variants such as Map.TrySet report ErrFull.
+func WithFixedCapacity(size int) OptionFunc {
+	return func(opt *option) {
+		opt.capacity = size
+		opt.fixed = true
+	}
+}
+
+// WithHasher shares hasher between maps, so that Map.Hash values can be reused across them.
+func WithHasher[K comparable](hasher maphash.Hasher[K]) OptionFunc {
+	return func(opt *option) {
+		opt.hasher = hasher
+	}
+}
+
+// WithOverwrite makes Ring drop the oldest element instead of failing when full.
+func WithOverwrite(enable bool) OptionFunc {
+	return func(opt *option) {
+		opt.overwrite = enable
+	}
+}
+
 func newOption() *option {
 	return &option{
-		capacity:   64,
-		loadFactor: 0.95,
+		capacity:    64,
+		loadFactor:  0.95,
+		migrateStep: 0,
+
+		minLoadFactor: 0,
+		fixed:         false,
+		hasher:        nil,
+		overwrite:     false,
+	}
+}
+
+func newHasher[K comparable](opt *option) maphash.Hasher[K] {
+	if opt.hasher == nil {
+		return maphash.NewHasher[K]()
 	}
+	h, ok := opt.hasher.(maphash.Hasher[K])
+	if ok != true {
+		panic(fmt.Sprintf("armap: hasher %T cannot be used for key type %T", opt.hasher, *new(K)))
+	}
+	return h
+}
+
+// sameHasher reports whether a and b share the seed, by comparing the hash of zero value
+func sameHasher[K comparable](a, b maphash.Hasher[K]) bool {
+	var zero K
+	return a.Hash(zero) == b.Hash(zero)
 }
@@ -1,5 +1,10 @@
 package armap
 
+import (
+	"iter"
+	"math/rand/v2"
+)
+
 type setValue struct{}
 
 type Set[K comparable] struct {
@@ -10,11 +15,29 @@ func (s *Set[K]) Len() int {
 	return s.m.Len()
 }
 
+// Add adds key, a new key is not added when the Set created by WithFixedCapacity is full; use TryAdd to detect it
 func (s *Set[K]) Add(key K) bool {
 	_, ok := s.m.Set(key, setValue{})
 	return ok
 }
 
+func (s *Set[K]) TryAdd(key K) (bool, error) {
+	_, ok, err := s.m.TrySet(key, setValue{})
+	return ok, err
+}
+
+func (s *Set[K]) AddMany(keys []K) {
+	s.m.SetMany(keys, make([]setValue, len(keys)))
+}
+
+func (s *Set[K]) ContainsMany(keys []K) []bool {
+	return s.m.GetMany(keys, make([]setValue, len(keys)))
+}
+
+func (s *Set[K]) DeleteMany(keys []K) int {
+	return s.m.DeleteMany(keys)
+}
+
 func (s *Set[K]) Contains(key K) bool {
 	_, ok := s.m.Get(key)
 	return ok
@@ -25,12 +48,53 @@ func (s *Set[K]) Delete(key K) bool {
 	return ok
 }
 
+func (s *Set[K]) DeleteFunc(fn func(K) bool) int {
+	return s.m.DeleteFunc(func(key K, value setValue) bool {
+		return fn(key)
+	})
+}
+
+// Pop removes and returns an arbitrary key
+func (s *Set[K]) Pop() (K, bool) {
+	k, _, ok := s.m.Pop()
+	return k, ok
+}
+
+// Random returns a random key without removing it
+func (s *Set[K]) Random(rng *rand.Rand) (K, bool) {
+	k, _, ok := s.m.Random(rng)
+	return k, ok
+}
+
+// Sample returns up to n distinct keys chosen at random
+func (s *Set[K]) Sample(n int, rng *rand.Rand) []K {
+	return s.m.Sample(n, rng)
+}
+
 func (s *Set[K]) Scan(iter func(K) bool) {
 	s.m.Scan(func(key K, value setValue) bool {
 		return iter(key)
 	})
 }
 
+func (s *Set[K]) Grow(n int) {
+	s.m.Grow(n)
+}
+
+func (s *Set[K]) BulkLoad(seq iter.Seq[K]) {
+	s.m.BulkLoad(func(yield func(K, setValue) bool) {
+		for k := range seq {
+			if yield(k, setValue{}) != true {
+				return
+			}
+		}
+	})
+}
+
+func (s *Set[K]) Shrink() {
+	s.m.Shrink()
+}
+
 func (s *Set[K]) Clear() {
 	s.m.Clear()
 }
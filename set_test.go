@@ -1,6 +1,8 @@
 package armap
 
 import (
+	"math/rand/v2"
+	"slices"
 	"strconv"
 	"testing"
 )
@@ -112,4 +114,128 @@ func TestSet(t *testing.T) {
 
 		NewSet[PrivateStruct](a)
 	})
+
+	t.Run("Shrink", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s := NewSet[int](a)
+
+		for i := 0; i < 1000; i += 1 {
+			s.Add(i)
+		}
+		for i := 10; i < 1000; i += 1 {
+			s.Delete(i)
+		}
+		s.Shrink()
+		if s.m.capacity != 16 {
+			tt.Errorf("capacity = %d, expect 16", s.m.capacity)
+		}
+		for i := 0; i < 10; i += 1 {
+			if s.Contains(i) != true {
+				tt.Errorf("key %d exists", i)
+			}
+		}
+	})
+
+	t.Run("BulkLoad", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s := NewSet[int](a)
+
+		keys := make([]int, 500)
+		for i := range keys {
+			keys[i] = i
+		}
+		s.BulkLoad(slices.Values(keys))
+		if s.Len() != len(keys) {
+			tt.Errorf("len = %d, expect %d", s.Len(), len(keys))
+		}
+		for _, k := range keys {
+			if s.Contains(k) != true {
+				tt.Errorf("key %d exists", k)
+			}
+		}
+	})
+
+	t.Run("AddMany/ContainsMany", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s := NewSet[int](a)
+
+		s.AddMany([]int{1, 2, 3, 2})
+		if s.Len() != 3 {
+			tt.Errorf("len = %d, expect 3", s.Len())
+		}
+		if found := s.ContainsMany([]int{1, 4, 3}); slices.Equal(found, []bool{true, false, true}) != true {
+			tt.Errorf("found = %v", found)
+		}
+		if n := s.DeleteMany([]int{1, 4}); n != 1 {
+			tt.Errorf("deleted = %d, expect 1", n)
+		}
+	})
+
+	t.Run("DeleteFunc", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s := NewSet[int](a)
+		for i := 0; i < 100; i += 1 {
+			s.Add(i)
+		}
+		if n := s.DeleteFunc(func(k int) bool { return 50 <= k }); n != 50 {
+			tt.Errorf("deleted = %d, expect 50", n)
+		}
+		for i := 0; i < 100; i += 1 {
+			if s.Contains(i) != (i < 50) {
+				tt.Errorf("contains(%d) = %v", i, s.Contains(i))
+			}
+		}
+	})
+
+	t.Run("PopRandomSample", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		rng := rand.New(rand.NewPCG(5, 6))
+		s := NewSet[string](a)
+		for i := 0; i < 100; i += 1 {
+			s.Add(strconv.Itoa(i))
+		}
+		if k, ok := s.Random(rng); ok != true || s.Contains(k) != true {
+			tt.Errorf("random = %s, %v", k, ok)
+		}
+		if keys := s.Sample(10, rng); len(keys) != 10 {
+			tt.Errorf("sample len = %d, expect 10", len(keys))
+		}
+		for i := 0; i < 100; i += 1 {
+			k, ok := s.Pop()
+			if ok != true {
+				tt.Fatalf("pop %d not found", i)
+			}
+			if s.Contains(k) {
+				tt.Errorf("key %s still exists", k)
+			}
+		}
+		if s.Len() != 0 {
+			tt.Errorf("len = %d, expect 0", s.Len())
+		}
+	})
+
+	t.Run("WithFixedCapacity", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		s := NewSet[int](a, WithFixedCapacity(8))
+
+		i := 0
+		for ; ; i += 1 {
+			if _, err := s.TryAdd(i); err != nil {
+				break
+			}
+		}
+		s.Add(-1)
+		if s.Contains(-1) {
+			tt.Errorf("-1 must not be added")
+		}
+		if s.Len() != i {
+			tt.Errorf("len = %d, expect %d", s.Len(), i)
+		}
+	})
 }
@@ -0,0 +1,280 @@
+package armap
+
+import (
+	"cmp"
+	"iter"
+)
+
+const skipMaxLevel = 32
+
+type skipLevel[K comparable, S cmp.Ordered] struct {
+	forward *skipNode[K, S]
+	span    int // number of nodes skipped by forward
+}
+
+type skipNode[K comparable, S cmp.Ordered] struct {
+	key    K
+	score  S
+	seq    uint64 // insertion order, breaks ties of equal scores
+	levels []skipLevel[K, S]
+}
+
+// less orders nodes by (score, seq)
+func (n *skipNode[K, S]) less(score S, seq uint64) bool {
+	if n.score != score {
+		return n.score < score
+	}
+	return n.seq < seq
+}
+
+// SortedSet is set of keys ordered by score with rank queries (Redis ZSET semantics).
+// it combines a skip list allocated in the arena with a Map index from key to node.
+type SortedSet[K comparable, S cmp.Ordered] struct {
+	na     TypeArena[skipNode[K, S]]
+	la     TypeArena[skipLevel[K, S]]
+	ka     TypeArena[K]
+	index  *Map[K, *skipNode[K, S]]
+	header *skipNode[K, S]
+	level  int
+	length int
+	seq    uint64
+	rnd    uint64
+}
+
+func (z *SortedSet[K, S]) Len() int {
+	return z.length
+}
+
+func (z *SortedSet[K, S]) randomLevel() int {
+	level := 1
+	for level < skipMaxLevel {
+		// xorshift64
+		z.rnd ^= z.rnd << 13
+		z.rnd ^= z.rnd >> 7
+		z.rnd ^= z.rnd << 17
+		if z.rnd&3 != 0 {
+			break
+		}
+		level += 1
+	}
+	return level
+}
+
+func (z *SortedSet[K, S]) newNode(level int, key K, score S) *skipNode[K, S] {
+	n := z.na.New()
+	n.key = key
+	n.score = score
+	n.levels = z.la.MakeSlice(level, level)
+	return n
+}
+
+// Add inserts key with score or updates its score, reports whether key is new.
+// a new key is not inserted when the SortedSet created by WithFixedCapacity is full; use TryAdd to detect it.
+func (z *SortedSet[K, S]) Add(key K, score S) bool {
+	added, _ := z.TryAdd(key, score)
+	return added
+}
+
+// TryAdd is Add that returns ErrFull when key is new and the SortedSet created by WithFixedCapacity is full
+func (z *SortedSet[K, S]) TryAdd(key K, score S) (bool, error) {
+	h := z.index.Hash(key)
+	b, found, err := z.index.entry(h, key)
+	if err != nil {
+		return false, err
+	}
+	if found {
+		if b.value.score == score {
+			return false, nil
+		}
+		// relink the same node, so that score updates do not allocate
+		n := b.value
+		z.unlink(n)
+		clear(n.levels)
+		n.score = score
+		z.link(n)
+		return false, nil
+	}
+	n := z.newNode(z.randomLevel(), z.ka.Clone(key), score)
+	z.link(n)
+	b.value = n
+	return true, nil
+}
+
+// link inserts n at the position of its score, n.levels must be cleared
+func (z *SortedSet[K, S]) link(n *skipNode[K, S]) {
+	z.seq += 1
+	n.seq = z.seq
+	score, seq := n.score, n.seq
+
+	var update [skipMaxLevel]*skipNode[K, S]
+	var rank [skipMaxLevel]int
+	x := z.header
+	for i := z.level - 1; 0 <= i; i -= 1 {
+		if i < z.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].forward != nil && x.levels[i].forward.less(score, seq) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].forward
+		}
+		update[i] = x
+	}
+
+	level := len(n.levels)
+	if z.level < level {
+		for i := z.level; i < level; i += 1 {
+			rank[i] = 0
+			update[i] = z.header
+			update[i].levels[i].span = z.length
+		}
+		z.level = level
+	}
+
+	for i := 0; i < level; i += 1 {
+		n.levels[i].forward = update[i].levels[i].forward
+		update[i].levels[i].forward = n
+
+		n.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = (rank[0] - rank[i]) + 1
+	}
+	for i := level; i < z.level; i += 1 {
+		update[i].levels[i].span += 1
+	}
+	z.length += 1
+}
+
+func (z *SortedSet[K, S]) unlink(n *skipNode[K, S]) {
+	var update [skipMaxLevel]*skipNode[K, S]
+	x := z.header
+	for i := z.level - 1; 0 <= i; i -= 1 {
+		for x.levels[i].forward != nil && x.levels[i].forward.less(n.score, n.seq) {
+			x = x.levels[i].forward
+		}
+		update[i] = x
+	}
+
+	for i := 0; i < z.level; i += 1 {
+		if update[i].levels[i].forward == n {
+			update[i].levels[i].span += n.levels[i].span - 1
+			update[i].levels[i].forward = n.levels[i].forward
+		} else {
+			update[i].levels[i].span -= 1
+		}
+	}
+	for 1 < z.level && z.header.levels[z.level-1].forward == nil {
+		z.level -= 1
+	}
+	z.length -= 1
+}
+
+func (z *SortedSet[K, S]) Score(key K) (score S, found bool) {
+	n, ok := z.index.Get(key)
+	if ok != true {
+		return
+	}
+	return n.score, true
+}
+
+// Rank returns the 0-based position of key in ascending score order
+func (z *SortedSet[K, S]) Rank(key K) (int, bool) {
+	n, ok := z.index.Get(key)
+	if ok != true {
+		return -1, false
+	}
+	rank := 0
+	x := z.header
+	for i := z.level - 1; 0 <= i; i -= 1 {
+		for x.levels[i].forward != nil && (x.levels[i].forward == n || x.levels[i].forward.less(n.score, n.seq)) {
+			rank += x.levels[i].span
+			x = x.levels[i].forward
+		}
+		if x == n {
+			return rank - 1, true
+		}
+	}
+	return -1, false
+}
+
+// nodeByRank returns the node at 0-based rank
+func (z *SortedSet[K, S]) nodeByRank(rank int) *skipNode[K, S] {
+	traversed := 0
+	x := z.header
+	for i := z.level - 1; 0 <= i; i -= 1 {
+		for x.levels[i].forward != nil && traversed+x.levels[i].span <= rank+1 {
+			traversed += x.levels[i].span
+			x = x.levels[i].forward
+		}
+		if traversed == rank+1 {
+			return x
+		}
+	}
+	return nil
+}
+
+// RangeByRank iterates keys whose rank is in [start, stop] in ascending order
+func (z *SortedSet[K, S]) RangeByRank(start, stop int) iter.Seq2[K, S] {
+	return func(yield func(K, S) bool) {
+		start = max(start, 0)
+		stop = min(stop, z.length-1)
+		if stop < start {
+			return
+		}
+		x := z.nodeByRank(start)
+		for i := start; i <= stop && x != nil; i += 1 {
+			if yield(x.key, x.score) != true {
+				return
+			}
+			x = x.levels[0].forward
+		}
+	}
+}
+
+// RangeByScore iterates keys whose score is in [min, max] in ascending order
+func (z *SortedSet[K, S]) RangeByScore(min, max S) iter.Seq2[K, S] {
+	return func(yield func(K, S) bool) {
+		x := z.header
+		for i := z.level - 1; 0 <= i; i -= 1 {
+			for x.levels[i].forward != nil && x.levels[i].forward.score < min {
+				x = x.levels[i].forward
+			}
+		}
+		for x = x.levels[0].forward; x != nil && x.score <= max; x = x.levels[0].forward {
+			if yield(x.key, x.score) != true {
+				return
+			}
+		}
+	}
+}
+
+func (z *SortedSet[K, S]) Remove(key K) bool {
+	n, ok := z.index.Delete(key)
+	if ok != true {
+		return false
+	}
+	z.unlink(n)
+	return true
+}
+
+func (z *SortedSet[K, S]) Clear() {
+	z.index.Clear()
+	z.header = z.newNode(skipMaxLevel, *new(K), *new(S))
+	z.level = 1
+	z.length = 0
+}
+
+func NewSortedSet[K comparable, S cmp.Ordered](arena Arena, funcs ...OptionFunc) *SortedSet[K, S] {
+	checkType[K](arena)
+
+	z := &SortedSet[K, S]{
+		na:     NewTypeArena[skipNode[K, S]](arena),
+		la:     NewTypeArena[skipLevel[K, S]](arena),
+		ka:     NewTypeArena[K](arena),
+		index:  NewMap[K, *skipNode[K, S]](arena, funcs...),
+		level:  1,
+		length: 0,
+		seq:    0,
+		rnd:    0x9e3779b97f4a7c15,
+	}
+	z.header = z.newNode(skipMaxLevel, *new(K), *new(S))
+	return z
+}
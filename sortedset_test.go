@@ -0,0 +1,201 @@
+package armap
+
+import (
+	"cmp"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestSortedSet(t *testing.T) {
+	t.Run("random", func(tt *testing.T) {
+		a := NewArena(16 * 1024 * 1024)
+		defer a.Release()
+		z := NewSortedSet[int, float64](a)
+
+		expect := make(map[int]float64)
+		r := rand.New(rand.NewPCG(1, 2))
+		for i := 0; i < 20_000; i += 1 {
+			key := r.IntN(2000)
+			if r.IntN(4) == 0 {
+				_, exists := expect[key]
+				if z.Remove(key) != exists {
+					tt.Fatalf("remove %d mismatch", key)
+				}
+				delete(expect, key)
+				continue
+			}
+			score := float64(r.IntN(500))
+			_, exists := expect[key]
+			if z.Add(key, score) == exists {
+				tt.Fatalf("add %d mismatch", key)
+			}
+			expect[key] = score
+		}
+
+		if z.Len() != len(expect) {
+			tt.Fatalf("len = %d, expect %d", z.Len(), len(expect))
+		}
+
+		keys := make([]int, 0, len(expect))
+		for k := range expect {
+			keys = append(keys, k)
+		}
+		actual := []int{}
+		for k, s := range z.RangeByRank(0, z.Len()-1) {
+			if expect[k] != s {
+				tt.Errorf("key %d score = %f, expect %f", k, s, expect[k])
+			}
+			actual = append(actual, k)
+		}
+		if len(actual) != len(keys) {
+			tt.Fatalf("range len = %d, expect %d", len(actual), len(keys))
+		}
+		if slices.IsSortedFunc(actual, func(a, b int) int { return cmp.Compare(expect[a], expect[b]) }) != true {
+			tt.Errorf("range is not sorted by score")
+		}
+		for i, k := range actual {
+			if rank, ok := z.Rank(k); ok != true || rank != i {
+				tt.Errorf("rank(%d) = %d, expect %d", k, rank, i)
+			}
+			if s, ok := z.Score(k); ok != true || s != expect[k] {
+				tt.Errorf("score(%d) = %f", k, s)
+			}
+		}
+	})
+
+	t.Run("exceed_buffer", func(tt *testing.T) {
+		// nodes and levels take much more than one arena buffer
+		a := NewArena(64 * 1024)
+		defer a.Release()
+		z := NewSortedSet[int, int](a)
+
+		expect := make(map[int]int)
+		r := rand.New(rand.NewPCG(3, 4))
+		for i := 0; i < 100_000; i += 1 {
+			key := r.IntN(5000)
+			if r.IntN(4) == 0 {
+				z.Remove(key)
+				delete(expect, key)
+				continue
+			}
+			score := r.IntN(1000)
+			z.Add(key, score)
+			expect[key] = score
+		}
+
+		if z.Len() != len(expect) {
+			tt.Fatalf("len = %d, expect %d", z.Len(), len(expect))
+		}
+		prev := -1
+		i := 0
+		for k, s := range z.RangeByRank(0, z.Len()-1) {
+			if expect[k] != s {
+				tt.Errorf("key %d score = %d, expect %d", k, s, expect[k])
+			}
+			if s < prev {
+				tt.Errorf("range is not sorted by score")
+			}
+			if rank, ok := z.Rank(k); ok != true || rank != i {
+				tt.Errorf("rank(%d) = %d, expect %d", k, rank, i)
+			}
+			prev = s
+			i += 1
+		}
+	})
+
+	t.Run("update_reuse", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		z := NewSortedSet[string, int](a)
+		for i := 0; i < 100; i += 1 {
+			z.Add(strconv.Itoa(i), i)
+		}
+
+		n, _ := z.index.Get("50")
+		for i := 0; i < 1000; i += 1 {
+			z.Add("50", i%200)
+		}
+		if updated, _ := z.index.Get("50"); updated != n {
+			tt.Errorf("score update must reuse the node")
+		}
+		if s, _ := z.Score("50"); s != 999%200 {
+			tt.Errorf("score = %d, expect %d", s, 999%200)
+		}
+		if rank, _ := z.Rank("0"); rank != 0 {
+			tt.Errorf("rank(0) = %d, expect 0", rank)
+		}
+		if z.Len() != 100 {
+			tt.Errorf("len = %d, expect 100", z.Len())
+		}
+	})
+
+	t.Run("leaderboard", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		z := NewSortedSet[string, int](a)
+		z.Add("alice", 30)
+		z.Add("bob", 10)
+		z.Add("carol", 20)
+		z.Add("dave", 40)
+		z.Add("bob", 50) // update
+
+		if rank, _ := z.Rank("bob"); rank != 3 {
+			tt.Errorf("rank(bob) = %d, expect 3", rank)
+		}
+		if _, ok := z.Rank("eve"); ok {
+			tt.Errorf("eve not exists")
+		}
+
+		names := []string{}
+		for k := range z.RangeByRank(1, 2) {
+			names = append(names, k)
+		}
+		if slices.Equal(names, []string{"alice", "dave"}) != true {
+			tt.Errorf("range by rank = %v", names)
+		}
+
+		names = names[:0]
+		for k := range z.RangeByScore(20, 40) {
+			names = append(names, k)
+		}
+		if slices.Equal(names, []string{"carol", "alice", "dave"}) != true {
+			tt.Errorf("range by score = %v", names)
+		}
+
+		if z.Remove("alice") != true || z.Len() != 3 {
+			tt.Errorf("remove alice")
+		}
+		if rank, _ := z.Rank("dave"); rank != 1 {
+			tt.Errorf("rank(dave) = %d, expect 1", rank)
+		}
+	})
+
+	t.Run("WithFixedCapacity", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		z := NewSortedSet[int, int](a, WithFixedCapacity(8))
+
+		i := 0
+		for ; ; i += 1 {
+			if _, err := z.TryAdd(i, i); err != nil {
+				if errors.Is(err, ErrFull) != true {
+					tt.Errorf("unexpected error: %+v", err)
+				}
+				break
+			}
+		}
+		if z.Add(-1, -1) {
+			tt.Errorf("-1 must not be added")
+		}
+		z.Add(0, 100)
+		if rank, _ := z.Rank(0); rank != i-1 {
+			tt.Errorf("rank(0) = %d, expect %d", rank, i-1)
+		}
+		if z.Len() != i {
+			tt.Errorf("len = %d, expect %d", z.Len(), i)
+		}
+	})
+}
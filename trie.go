@@ -0,0 +1,323 @@
+package armap
+
+import (
+	"iter"
+	"slices"
+)
+
+// children are kept in sorted small arrays up to this size, then in a direct 256 array
+const trieSmallChildren = 48
+
+type trieNode[V any] struct {
+	prefix      string // compressed edge label
+	value       V
+	hasValue    bool
+	full        bool
+	numChildren int
+	labels      []byte         // sorted first bytes of children (small node)
+	children    []*trieNode[V] // parallel to labels, or indexed by byte (full node)
+}
+
+func (n *trieNode[V]) child(c byte) *trieNode[V] {
+	if n.full {
+		return n.children[c]
+	}
+	if i, ok := slices.BinarySearch(n.labels, c); ok {
+		return n.children[i]
+	}
+	return nil
+}
+
+// TrieMap is radix tree keyed by string with prefix queries.
+// nodes are allocated in the arena, child arrays adapt from sorted small arrays to direct 256 arrays.
+type TrieMap[V any] struct {
+	arena Arena
+	na    TypeArena[trieNode[V]]
+	ca    TypeArena[*trieNode[V]]
+	ba    TypeArena[byte]
+	va    TypeArena[V]
+	root  *trieNode[V]
+	count int
+}
+
+func (t *TrieMap[V]) Len() int {
+	return t.count
+}
+
+func (t *TrieMap[V]) newNode(prefix string) *trieNode[V] {
+	n := t.na.New()
+	n.prefix = prefix
+	return n
+}
+
+func (t *TrieMap[V]) setChild(n *trieNode[V], c byte, child *trieNode[V]) {
+	if n.full {
+		if n.children[c] == nil {
+			n.numChildren += 1
+		}
+		n.children[c] = child
+		return
+	}
+	i, ok := slices.BinarySearch(n.labels, c)
+	if ok {
+		n.children[i] = child
+		return
+	}
+	if n.numChildren == trieSmallChildren {
+		children := t.ca.MakeSlice(256, 256)
+		for j, l := range n.labels {
+			children[l] = n.children[j]
+		}
+		children[c] = child
+		n.full = true
+		n.labels = nil
+		n.children = children
+		n.numChildren += 1
+		return
+	}
+	if n.numChildren == cap(n.labels) {
+		size := min(max(cap(n.labels)*2, 8), trieSmallChildren)
+		labels := t.ba.MakeSlice(n.numChildren, size)
+		children := t.ca.MakeSlice(n.numChildren, size)
+		copy(labels, n.labels)
+		copy(children, n.children)
+		n.labels = labels
+		n.children = children
+	}
+	n.labels = slices.Insert(n.labels, i, c)
+	n.children = slices.Insert(n.children, i, child)
+	n.numChildren += 1
+}
+
+func (t *TrieMap[V]) removeChild(n *trieNode[V], c byte) {
+	if n.full {
+		n.children[c] = nil
+		n.numChildren -= 1
+		return
+	}
+	if i, ok := slices.BinarySearch(n.labels, c); ok {
+		n.labels = slices.Delete(n.labels, i, i+1)
+		n.children = slices.Delete(n.children, i, i+1)
+		n.numChildren -= 1
+	}
+}
+
+// onlyChild returns the child of n that has exactly one child
+func (n *trieNode[V]) onlyChild() *trieNode[V] {
+	if n.full {
+		for _, c := range n.children {
+			if c != nil {
+				return c
+			}
+		}
+		return nil
+	}
+	return n.children[0]
+}
+
+func (t *TrieMap[V]) Set(key string, value V) (old V, found bool) {
+	n := t.root
+	rest := key
+	for {
+		if rest == "" {
+			old, found = n.value, n.hasValue
+			n.value = t.va.Clone(value)
+			n.hasValue = true
+			if found != true {
+				t.count += 1
+			}
+			return
+		}
+
+		c := n.child(rest[0])
+		if c == nil {
+			leaf := t.newNode(cloneString(t.arena, rest))
+			leaf.value = t.va.Clone(value)
+			leaf.hasValue = true
+			t.setChild(n, rest[0], leaf)
+			t.count += 1
+			return
+		}
+
+		common := commonPrefixLen(c.prefix, rest)
+		if common == len(c.prefix) {
+			n = c
+			rest = rest[common:]
+			continue
+		}
+
+		// split edge of c at common
+		mid := t.newNode(c.prefix[:common])
+		c.prefix = c.prefix[common:]
+		t.setChild(mid, c.prefix[0], c)
+		t.setChild(n, rest[0], mid)
+		n = mid
+		rest = rest[common:]
+	}
+}
+
+func (t *TrieMap[V]) find(key string) *trieNode[V] {
+	n := t.root
+	rest := key
+	for rest != "" {
+		c := n.child(rest[0])
+		if c == nil || len(rest) < len(c.prefix) || rest[:len(c.prefix)] != c.prefix {
+			return nil
+		}
+		n = c
+		rest = rest[len(c.prefix):]
+	}
+	return n
+}
+
+func (t *TrieMap[V]) Get(key string) (value V, found bool) {
+	if n := t.find(key); n != nil && n.hasValue {
+		return n.value, true
+	}
+	return
+}
+
+func (t *TrieMap[V]) Delete(key string) (old V, found bool) {
+	var parent, grand *trieNode[V]
+	n := t.root
+	rest := key
+	for rest != "" {
+		c := n.child(rest[0])
+		if c == nil || len(rest) < len(c.prefix) || rest[:len(c.prefix)] != c.prefix {
+			return
+		}
+		grand, parent, n = parent, n, c
+		rest = rest[len(c.prefix):]
+	}
+	if n.hasValue != true {
+		return
+	}
+
+	old, found = n.value, true
+	var zero V
+	n.value = zero
+	n.hasValue = false
+	t.count -= 1
+
+	if n == t.root {
+		return
+	}
+	switch n.numChildren {
+	case 0:
+		t.removeChild(parent, n.prefix[0])
+		// parent may now be a pass-through node
+		if parent != t.root && parent.hasValue != true && parent.numChildren == 1 {
+			t.merge(grand, parent)
+		}
+	case 1:
+		t.merge(parent, n)
+	}
+	return
+}
+
+// merge replaces n, which has no value and one child, by its child with concatenated prefix
+func (t *TrieMap[V]) merge(parent, n *trieNode[V]) {
+	c := n.onlyChild()
+	c.prefix = cloneString(t.arena, n.prefix+c.prefix)
+	t.setChild(parent, c.prefix[0], c)
+}
+
+// LongestPrefix returns the longest key that is a prefix of key
+func (t *TrieMap[V]) LongestPrefix(key string) (prefix string, value V, found bool) {
+	n := t.root
+	consumed := 0
+	if n.hasValue {
+		prefix, value, found = "", n.value, true
+	}
+	for consumed < len(key) {
+		c := n.child(key[consumed])
+		rest := key[consumed:]
+		if c == nil || len(rest) < len(c.prefix) || rest[:len(c.prefix)] != c.prefix {
+			break
+		}
+		n = c
+		consumed += len(c.prefix)
+		if n.hasValue {
+			prefix, value, found = key[:consumed], n.value, true
+		}
+	}
+	return
+}
+
+// WalkPrefix iterates keys starting with prefix in lexical order
+func (t *TrieMap[V]) WalkPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		n := t.root
+		buf := make([]byte, 0, 64)
+		rest := prefix
+		for rest != "" {
+			c := n.child(rest[0])
+			if c == nil {
+				return
+			}
+			if len(rest) <= len(c.prefix) {
+				if c.prefix[:len(rest)] != rest {
+					return
+				}
+			} else if rest[:len(c.prefix)] != c.prefix {
+				return
+			}
+			buf = append(buf, c.prefix...)
+			n = c
+			rest = rest[min(len(rest), len(c.prefix)):]
+		}
+		t.walk(n, buf, yield)
+	}
+}
+
+// All iterates all keys in lexical order
+func (t *TrieMap[V]) All() iter.Seq2[string, V] {
+	return t.WalkPrefix("")
+}
+
+func (t *TrieMap[V]) walk(n *trieNode[V], buf []byte, yield func(string, V) bool) bool {
+	if n.hasValue {
+		if yield(string(buf), n.value) != true {
+			return false
+		}
+	}
+	for _, c := range n.children {
+		if c == nil {
+			continue
+		}
+		if t.walk(c, append(buf, c.prefix...), yield) != true {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *TrieMap[V]) Clear() {
+	t.root = t.newNode("")
+	t.count = 0
+}
+
+func NewTrieMap[V any](arena Arena) *TrieMap[V] {
+	checkType[V](arena)
+
+	t := &TrieMap[V]{
+		arena: arena,
+		na:    NewTypeArena[trieNode[V]](arena),
+		ca:    NewTypeArena[*trieNode[V]](arena),
+		ba:    NewTypeArena[byte](arena),
+		va:    NewTypeArena[V](arena),
+		count: 0,
+	}
+	t.root = t.newNode("")
+	return t
+}
+
+func commonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i += 1 {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,176 @@
+package armap
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTrieMap(t *testing.T) {
+	t.Run("Set/Get/Delete", func(tt *testing.T) {
+		a := NewArena(16 * 1024 * 1024)
+		defer a.Release()
+		m := NewTrieMap[int](a)
+
+		expect := make(map[string]int)
+		r := rand.New(rand.NewPCG(1, 2))
+		for i := 0; i < 20_000; i += 1 {
+			key := fmt.Sprintf("/api/v%d/%c/%d", r.IntN(3), 'a'+rune(r.IntN(60)), r.IntN(50))
+			if r.IntN(3) == 0 {
+				_, exists := expect[key]
+				if _, ok := m.Delete(key); ok != exists {
+					tt.Fatalf("delete %s = %v, expect %v", key, ok, exists)
+				}
+				delete(expect, key)
+				continue
+			}
+			_, exists := expect[key]
+			if _, ok := m.Set(key, i); ok != exists {
+				tt.Fatalf("set %s = %v, expect %v", key, ok, exists)
+			}
+			expect[key] = i
+		}
+
+		if m.Len() != len(expect) {
+			tt.Errorf("len = %d, expect %d", m.Len(), len(expect))
+		}
+		for k, v := range expect {
+			if actual, ok := m.Get(k); ok != true || actual != v {
+				tt.Errorf("key %s value = %d, expect %d", k, actual, v)
+			}
+		}
+		if _, ok := m.Get("/api"); ok {
+			tt.Errorf("/api is not set")
+		}
+
+		keys := slices.Sorted(maps.Keys(expect))
+		actual := []string{}
+		for k := range m.All() {
+			actual = append(actual, k)
+		}
+		if slices.Equal(actual, keys) != true {
+			tt.Errorf("All is not ordered or mismatch")
+		}
+	})
+
+	t.Run("exceed_buffer", func(tt *testing.T) {
+		// nodes, labels and child arrays take much more than one arena buffer
+		a := NewArena(64 * 1024)
+		defer a.Release()
+		m := NewTrieMap[int](a)
+
+		expect := make(map[string]int)
+		r := rand.New(rand.NewPCG(3, 4))
+		for i := 0; i < 50_000; i += 1 {
+			key := fmt.Sprintf("/%c/%d/%c", 'a'+rune(r.IntN(60)), r.IntN(500), 'a'+rune(r.IntN(26)))
+			if r.IntN(3) == 0 {
+				m.Delete(key)
+				delete(expect, key)
+				continue
+			}
+			m.Set(key, i)
+			expect[key] = i
+		}
+
+		if m.Len() != len(expect) {
+			tt.Errorf("len = %d, expect %d", m.Len(), len(expect))
+		}
+		for k, v := range expect {
+			if actual, ok := m.Get(k); ok != true || actual != v {
+				tt.Errorf("key %s value = %d, expect %d", k, actual, v)
+			}
+		}
+		actual := []string{}
+		for k := range m.All() {
+			actual = append(actual, k)
+		}
+		if slices.Equal(actual, slices.Sorted(maps.Keys(expect))) != true {
+			tt.Errorf("All is not ordered or mismatch")
+		}
+	})
+
+	t.Run("WalkPrefix", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewTrieMap[string](a)
+		for _, k := range []string{"/api/v1/users", "/api/v1/users/1", "/api/v1/user", "/api/v1/groups", "/api/v2/users", "/"} {
+			m.Set(k, strings.ToUpper(k))
+		}
+
+		actual := []string{}
+		for k, v := range m.WalkPrefix("/api/v1/users") {
+			if v != strings.ToUpper(k) {
+				tt.Errorf("key %s value %s", k, v)
+			}
+			actual = append(actual, k)
+		}
+		if slices.Equal(actual, []string{"/api/v1/users", "/api/v1/users/1"}) != true {
+			tt.Errorf("walk = %v", actual)
+		}
+
+		actual = actual[:0]
+		for k := range m.WalkPrefix("/api/v1/u") {
+			actual = append(actual, k)
+		}
+		if slices.Equal(actual, []string{"/api/v1/user", "/api/v1/users", "/api/v1/users/1"}) != true {
+			tt.Errorf("walk = %v", actual)
+		}
+
+		for range m.WalkPrefix("/api/v3") {
+			tt.Errorf("no key with /api/v3")
+		}
+	})
+
+	t.Run("LongestPrefix", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewTrieMap[int](a)
+		m.Set("/", 1)
+		m.Set("/api", 2)
+		m.Set("/api/v1/users", 3)
+
+		cases := []struct {
+			key    string
+			prefix string
+			value  int
+		}{
+			{"/api/v1/users/123", "/api/v1/users", 3},
+			{"/api/v1/groups", "/api", 2},
+			{"/apix", "/api", 2},
+			{"/static", "/", 1},
+		}
+		for _, c := range cases {
+			prefix, value, ok := m.LongestPrefix(c.key)
+			if ok != true || prefix != c.prefix || value != c.value {
+				tt.Errorf("LongestPrefix(%s) = %s %d %v", c.key, prefix, value, ok)
+			}
+		}
+		if _, _, ok := m.LongestPrefix("static"); ok {
+			tt.Errorf("no prefix for static")
+		}
+	})
+
+	t.Run("full_node", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		m := NewTrieMap[int](a)
+		for i := 0; i < 256; i += 1 {
+			m.Set(string([]byte{'k', byte(255 - i)}), i)
+		}
+		for i := 0; i < 256; i += 1 {
+			if v, ok := m.Get(string([]byte{'k', byte(255 - i)})); ok != true || v != i {
+				tt.Errorf("key %d value = %d", i, v)
+			}
+		}
+		prev := ""
+		for k := range m.All() {
+			if k < prev {
+				tt.Errorf("not ordered %q < %q", k, prev)
+			}
+			prev = k
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package armap
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"unsafe"
+)
+
+// Vector is growable slice on the arena.
+// elements are stored in fixed size chunks, so growth never copies or abandons existing elements.
+type Vector[T any] struct {
+	ta     TypeArena[T]
+	chunks [][]T
+	shift  uint
+	mask   int
+	length int
+}
+
+func (v *Vector[T]) Len() int {
+	return v.length
+}
+
+func (v *Vector[T]) Append(values ...T) {
+	for _, value := range values {
+		if v.length == len(v.chunks)<<v.shift {
+			v.chunks = append(v.chunks, v.ta.MakeSlice(v.mask+1, v.mask+1))
+		}
+		v.chunks[v.length>>v.shift][v.length&v.mask] = v.ta.Clone(value)
+		v.length += 1
+	}
+}
+
+func (v *Vector[T]) checkIndex(i int) {
+	if i < 0 || v.length <= i {
+		panic(fmt.Sprintf("armap: index out of range [%d] with length %d", i, v.length))
+	}
+}
+
+func (v *Vector[T]) At(i int) T {
+	v.checkIndex(i)
+	return v.chunks[i>>v.shift][i&v.mask]
+}
+
+func (v *Vector[T]) Set(i int, value T) {
+	v.checkIndex(i)
+	v.chunks[i>>v.shift][i&v.mask] = v.ta.Clone(value)
+}
+
+func (v *Vector[T]) Pop() (value T, ok bool) {
+	if v.length == 0 {
+		return
+	}
+	v.length -= 1
+	p := &v.chunks[v.length>>v.shift][v.length&v.mask]
+	value = *p
+	var zero T
+	*p = zero
+	return value, true
+}
+
+// Truncate shrinks the length to n, allocated chunks are kept for reuse
+func (v *Vector[T]) Truncate(n int) {
+	if n < 0 || v.length <= n {
+		return
+	}
+	var zero T
+	for i := n; i < v.length; i += 1 {
+		v.chunks[i>>v.shift][i&v.mask] = zero
+	}
+	v.length = n
+}
+
+func (v *Vector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < v.length; i += 1 {
+			if yield(i, v.chunks[i>>v.shift][i&v.mask]) != true {
+				return
+			}
+		}
+	}
+}
+
+// NewVector creates Vector, WithCapacity sets the number of elements per chunk,
+// limited to what fits into one arena buffer.
+func NewVector[T any](arena Arena, funcs ...OptionFunc) *Vector[T] {
+	checkType[T](arena)
+
+	opt := newOption()
+	for _, fn := range funcs {
+		fn(opt)
+	}
+
+	// at least 8 elements per chunk (when they fit) keeps the arena pointer aligned for any element size
+	chunkSize := 8
+	for chunkSize < opt.capacity {
+		chunkSize *= 2
+	}
+	// a chunk must fit into one arena buffer
+	var t T
+	size := max(int(unsafe.Sizeof(t)), 1)
+	for 1 < chunkSize && arena.chunkSize() < chunkSize*size {
+		chunkSize /= 2
+	}
+
+	return &Vector[T]{
+		ta:     NewTypeArena[T](arena),
+		chunks: nil,
+		shift:  uint(bits.TrailingZeros(uint(chunkSize))),
+		mask:   chunkSize - 1,
+		length: 0,
+	}
+}
@@ -0,0 +1,112 @@
+package armap
+
+import (
+	"testing"
+)
+
+func TestVector(t *testing.T) {
+	t.Run("Append/At/Set", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		v := NewVector[int](a, WithCapacity(16))
+
+		for i := 0; i < 1000; i += 1 {
+			v.Append(i)
+		}
+		if v.Len() != 1000 {
+			tt.Errorf("len = %d, expect 1000", v.Len())
+		}
+		if len(v.chunks) != 63 {
+			tt.Errorf("chunks = %d, expect 63", len(v.chunks))
+		}
+		for i := 0; i < 1000; i += 1 {
+			if n := v.At(i); n != i {
+				tt.Errorf("At(%d) = %d", i, n)
+			}
+		}
+		v.Set(500, -1)
+		if n := v.At(500); n != -1 {
+			tt.Errorf("At(500) = %d, expect -1", n)
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					tt.Errorf("expected panic for out of range")
+				}
+			}()
+			v.At(1000)
+		}()
+	})
+
+	t.Run("Pop/Truncate", func(tt *testing.T) {
+		a := NewArena(1024 * 1024)
+		defer a.Release()
+		v := NewVector[string](a)
+
+		v.Append("a", "b", "c", "d")
+		if s, ok := v.Pop(); ok != true || s != "d" {
+			tt.Errorf("pop = %s", s)
+		}
+		v.Truncate(1)
+		if v.Len() != 1 {
+			tt.Errorf("len = %d, expect 1", v.Len())
+		}
+		v.Append("x")
+
+		actual := []string{}
+		for i, s := range v.All() {
+			if v.At(i) != s {
+				tt.Errorf("At(%d) = %s, expect %s", i, v.At(i), s)
+			}
+			actual = append(actual, s)
+		}
+		if len(actual) != 2 || actual[0] != "a" || actual[1] != "x" {
+			tt.Errorf("all = %v", actual)
+		}
+
+		v.Truncate(0)
+		if _, ok := v.Pop(); ok {
+			tt.Errorf("empty vector")
+		}
+	})
+
+	t.Run("exceed_buffer", func(tt *testing.T) {
+		a := NewArena(4 * 1024)
+		defer a.Release()
+		v := NewVector[int](a, WithCapacity(64))
+
+		for i := 0; i < 10000; i += 1 {
+			v.Append(i)
+		}
+		for i := 0; i < 10000; i += 1 {
+			if n := v.At(i); n != i {
+				tt.Errorf("at(%d) = %d, expect %d", i, n, i)
+			}
+		}
+	})
+
+	t.Run("chunk_fits_buffer", func(tt *testing.T) {
+		a := NewArena(4 * 1024)
+		defer a.Release()
+		v := NewVector[[128]byte](a)
+		for i := 0; i < 100; i += 1 {
+			v.Append([128]byte{byte(i)})
+		}
+		for i := 0; i < 100; i += 1 {
+			if b := v.At(i); b[0] != byte(i) {
+				tt.Errorf("at(%d) = %d", i, b[0])
+			}
+		}
+
+		b := NewArena(1024 * 1024)
+		defer b.Release()
+		w := NewVector[int](b, WithCapacity(1<<20))
+		for i := 0; i < 200_000; i += 1 {
+			w.Append(i)
+		}
+		if n := w.At(199_999); n != 199_999 {
+			tt.Errorf("at = %d", n)
+		}
+	})
+}